@@ -0,0 +1,36 @@
+// Command schemagen writes the JSON Schema and the annotated example of the
+// server configuration.
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/MagicRodri/grpc_with_go/config"
+	"github.com/spf13/pflag"
+)
+
+func main() {
+	schemaPath := pflag.String("schema", "config/config.schema.json", "Path of the generated JSON Schema")
+	examplePath := pflag.String("example", "config/config.example.yaml", "Path of the generated example config")
+	pflag.Parse()
+
+	data, err := json.MarshalIndent(config.JSONSchema(), "", "  ")
+	if err != nil {
+		log.Fatalf("Error encoding schema: %v", err)
+	}
+	if err := os.WriteFile(*schemaPath, append(data, '\n'), 0o644); err != nil {
+		log.Fatalf("Error writing schema: %v", err)
+	}
+
+	// the example references the schema relative to its own directory
+	ref, err := filepath.Rel(filepath.Dir(*examplePath), *schemaPath)
+	if err != nil {
+		ref = *schemaPath
+	}
+	if err := os.WriteFile(*examplePath, config.Example(filepath.ToSlash(ref)), 0o644); err != nil {
+		log.Fatalf("Error writing example: %v", err)
+	}
+}
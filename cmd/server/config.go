@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/MagicRodri/grpc_with_go/config"
+	"github.com/spf13/pflag"
+)
+
+// configFlags are the flags of every command that loads the configuration.
+type configFlags struct {
+	fs      *pflag.FlagSet
+	path    *string
+	profile *string
+}
+
+func addConfigFlags(fs *pflag.FlagSet) *configFlags {
+	cf := &configFlags{
+		fs:      fs,
+		path:    fs.String("config", "config/config.yaml", "Path to the configuration file, empty to configure from env and flags only"),
+		profile: fs.String("profile", os.Getenv("APP_PROFILE"), "Config profile merged over the configuration file, e.g. prod for config.prod.yaml (env APP_PROFILE)"),
+	}
+	config.BindFlags(fs)
+	return cf
+}
+
+func (cf *configFlags) load() (*config.Config, []config.Setting, error) {
+	cfg, settings, err := config.ResolveConfig(*cf.path, config.WithProfile(*cf.profile), config.WithFlags(cf.fs))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading config: %w", err)
+	}
+	return cfg, settings, nil
+}
+
+func runConfigValidate(args []string) error {
+	fs := newFlagSet("config validate")
+	cf := addConfigFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if _, _, err := cf.load(); err != nil {
+		return err
+	}
+	fmt.Println("configuration is valid")
+	return nil
+}
+
+func runConfigPrint(args []string) error {
+	fs := newFlagSet("config print")
+	cf := addConfigFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	_, settings, err := cf.load()
+	if err != nil {
+		return err
+	}
+	return config.WriteSettings(os.Stdout, settings)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func runHealthcheck(args []string) error {
+	fs := newFlagSet("healthcheck")
+	cf := addConfigFlags(fs)
+	address := fs.String("address", "", "Address to check, the first listener address of the configuration by default")
+	service := fs.String("service", "", "Service to check, the whole server by default")
+	timeout := fs.Duration("timeout", 5*time.Second, "Timeout of the check")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	target := *address
+	if target == "" {
+		cfg, _, err := cf.load()
+		if err != nil {
+			return err
+		}
+		for _, endpoint := range cfg.GRPC.Endpoints() {
+			// inherited sockets have no address to dial in the config
+			if !strings.HasPrefix(endpoint.Address, "fd://") {
+				target = dialTarget(endpoint.Address)
+				break
+			}
+		}
+		if target == "" {
+			return usageError{errors.New("no listener address in the configuration, set --address"), fs.Usage}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+	defer conn.Close()
+
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: *service})
+	if err != nil {
+		return fmt.Errorf("health check of %s failed: %w", target, err)
+	}
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%s is %s", target, res.GetStatus())
+	}
+	fmt.Println(res.GetStatus())
+	return nil
+}
+
+// dialTarget turns a listen address into a target a client can dial. An empty
+// or unspecified host means the server listens on every interface, loopback
+// included; unix sockets use the unix: target of grpc.
+func dialTarget(listen string) string {
+	if path, ok := strings.CutPrefix(listen, "unix://"); ok {
+		return "unix:" + path
+	}
+	listen = strings.TrimPrefix(listen, "tcp://")
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return listen
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -1,27 +1,140 @@
 package main
 
 import (
-	"context"
-	"flag"
-	"log"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 
-	"github.com/MagicRodri/grpc_with_go/config"
-	"github.com/MagicRodri/grpc_with_go/internal/grpc"
+	"github.com/spf13/pflag"
 )
 
+// Exit codes of the server binary.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// command is a subcommand of the server binary. Nested commands such as
+// "config print" are listed with their full name.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// usageError makes the binary exit with exitUsage after printing usage.
+type usageError struct {
+	err   error
+	usage func()
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e usageError) Unwrap() error {
+	return e.err
+}
+
+var commands = []command{
+	{name: "serve", summary: "Run the gRPC server (default)", run: runServe},
+	{name: "config validate", summary: "Load and validate the configuration", run: runConfigValidate},
+	{name: "config print", summary: "Print the effective configuration and the source of every value", run: runConfigPrint},
+	{name: "version", summary: "Print the build information", run: runVersion},
+	{name: "healthcheck", summary: "Check that the server reports SERVING, for container probes", run: runHealthcheck},
+}
+
 func main() {
-	configPath := flag.String("config", "config/config.yaml", "Path to the configuration file")
-	flag.Parse()
-	cfg, err := config.LoadConfig(*configPath)
-	if err != nil {
-		log.Printf("Error loading config: %v\n", err)
-		return
-	}
-	server := grpc.NewServer(&cfg.GRPC)
-	if err := server.Serve(context.Background()); err != nil {
-		log.Printf("Error starting gRPC server: %v\n", err)
-		return
-	}
-	defer server.Stop()
-	log.Println("gRPC server started successfully")
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	cmd, rest, err := findCommand(args)
+	if err == nil {
+		err = cmd.run(rest)
+	}
+
+	var usage usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, pflag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usage):
+		fmt.Fprintf(os.Stderr, "server: %v\n\n", err)
+		usage.usage()
+		return exitUsage
+	default:
+		fmt.Fprintf(os.Stderr, "server: %v\n", err)
+		return exitError
+	}
+}
+
+// findCommand selects the longest command matching the leading arguments.
+// Without a command, or with flags only, the server is started as before
+// subcommands existed.
+func findCommand(args []string) (command, []string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+			printUsage()
+			return command{}, nil, pflag.ErrHelp
+		}
+		return commands[0], args, nil
+	}
+	if args[0] == "help" {
+		printUsage()
+		return command{}, nil, pflag.ErrHelp
+	}
+
+	var found *command
+	var rest []string
+	for i := range commands {
+		words := strings.Fields(commands[i].name)
+		if len(words) > len(args) || strings.Join(args[:len(words)], " ") != commands[i].name {
+			continue
+		}
+		if found == nil || len(words) > len(strings.Fields(found.name)) {
+			found, rest = &commands[i], args[len(words):]
+		}
+	}
+	if found == nil {
+		return command{}, nil, usageError{fmt.Errorf("unknown command %q", strings.Join(args, " ")), printUsage}
+	}
+	return *found, rest, nil
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: server <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `Run "server <command> --help" for the flags of a command.`)
+}
+
+// newFlagSet creates the flag set of a command; parse errors are usage errors.
+func newFlagSet(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: server %s [flags]\n\nFlags:\n", name)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func parseFlags(fs *pflag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return err
+		}
+		return usageError{err, fs.Usage}
+	}
+	if fs.NArg() > 0 {
+		return usageError{fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " ")), fs.Usage}
+	}
+	return nil
 }
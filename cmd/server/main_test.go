@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestFindCommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		wantName string
+		wantRest []string
+		wantErr  error
+	}{
+		{args: nil, wantName: "serve"},
+		{args: []string{"--config", "app.yaml"}, wantName: "serve", wantRest: []string{"--config", "app.yaml"}},
+		{args: []string{"serve", "--profile", "prod"}, wantName: "serve", wantRest: []string{"--profile", "prod"}},
+		{args: []string{"config", "print", "--config", ""}, wantName: "config print", wantRest: []string{"--config", ""}},
+		{args: []string{"config", "validate"}, wantName: "config validate"},
+		{args: []string{"help"}, wantErr: pflag.ErrHelp},
+		{args: []string{"--help"}, wantErr: pflag.ErrHelp},
+	}
+
+	for _, tt := range tests {
+		cmd, rest, err := findCommand(tt.args)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%q: error = %v, want %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if cmd.name != tt.wantName || !slices.Equal(rest, tt.wantRest) {
+			t.Errorf("%q: command %q with %q, want %q with %q", tt.args, cmd.name, rest, tt.wantName, tt.wantRest)
+		}
+	}
+
+	for _, args := range [][]string{{"config"}, {"bogus"}, {"config", "bogus"}} {
+		var usage usageError
+		if _, _, err := findCommand(args); !errors.As(err, &usage) {
+			t.Errorf("%q: error = %v, want a usage error", args, err)
+		}
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunExitCodes(t *testing.T) {
+	valid := writeConfig(t, "grpc:\n  address: localhost:50051\n")
+	invalid := writeConfig(t, "grpc:\n  address: localhost\n")
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{args: []string{"config", "validate", "--config", valid}, want: exitOK},
+		{args: []string{"config", "validate", "--config", invalid}, want: exitError},
+		{args: []string{"config", "validate", "--config", filepath.Join(t.TempDir(), "missing.yaml")}, want: exitError},
+		{args: []string{"config", "validate", "--unknown"}, want: exitUsage},
+		{args: []string{"config", "validate", "extra"}, want: exitUsage},
+		{args: []string{"version", "--help"}, want: exitOK},
+		{args: []string{"bogus"}, want: exitUsage},
+		{args: []string{"help"}, want: exitOK},
+	}
+
+	for _, tt := range tests {
+		if code := run(tt.args); code != tt.want {
+			t.Errorf("%q: exit code %d, want %d", tt.args, code, tt.want)
+		}
+	}
+}
+
+func TestDialTarget(t *testing.T) {
+	tests := map[string]string{
+		":50051":                "localhost:50051",
+		"0.0.0.0:50051":         "localhost:50051",
+		"[::]:50051":            "localhost:50051",
+		"10.0.0.1:50051":        "10.0.0.1:50051",
+		"tcp://grpc.local:443":  "grpc.local:443",
+		"unix:///run/grpc.sock": "unix:/run/grpc.sock",
+	}
+
+	for listen, want := range tests {
+		if got := dialTarget(listen); got != want {
+			t.Errorf("dialTarget(%q) = %q, want %q", listen, got, want)
+		}
+	}
+}
+
+func TestHealthcheckDialsFirstListener(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(lis)
+	defer server.Stop()
+
+	_, port, _ := net.SplitHostPort(lis.Addr().String())
+	// inherited sockets are skipped, the unspecified host is dialed on loopback
+	path := writeConfig(t, "grpc:\n  listeners:\n    - address: fd://3\n    - address: 0.0.0.0:"+port+"\n")
+	if code := run([]string{"healthcheck", "--config", path, "--timeout", "5s"}); code != exitOK {
+		t.Fatalf("healthcheck exit code %d, want %d", code, exitOK)
+	}
+
+	onlyInherited := writeConfig(t, "grpc:\n  listeners:\n    - address: fd://3\n")
+	if code := run([]string{"healthcheck", "--config", onlyInherited}); code != exitUsage {
+		t.Fatalf("healthcheck without a dialable listener: exit code %d, want %d", code, exitUsage)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/MagicRodri/grpc_with_go/internal/grpc"
+	"github.com/MagicRodri/grpc_with_go/pkg/logger"
+)
+
+// reexecTimeout bounds the startup of the process taking over the listeners.
+const reexecTimeout = 30 * time.Second
+
+func runServe(args []string) error {
+	fs := newFlagSet("serve")
+	cf := addConfigFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, _, err := cf.load()
+	if err != nil {
+		return err
+	}
+	if err := logger.InitDefault(&cfg.Logger); err != nil {
+		return fmt.Errorf("error initializing logger: %w", err)
+	}
+	defer logger.Close()
+	logger.Component("server").Debug("loaded configuration", "config", cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	server, err := grpc.NewServer(&cfg.GRPC)
+	if err != nil {
+		return fmt.Errorf("error creating gRPC server: %w", err)
+	}
+	go handleReexec(ctx, server, stop)
+	if err := server.Serve(ctx); err != nil {
+		return fmt.Errorf("error running gRPC server: %w", err)
+	}
+	return nil
+}
+
+// handleReexec hands the listeners over to a new process on reexecSignals,
+// e.g. after the binary was replaced, and then stops this server.
+func handleReexec(ctx context.Context, server *grpc.Server, stop context.CancelFunc) {
+	if len(reexecSignals) == 0 {
+		return
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, reexecSignals...)
+	defer signal.Stop(signals)
+
+	log := logger.Component("server")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+		}
+
+		reexecCtx, cancel := context.WithTimeout(ctx, reexecTimeout)
+		err := server.Reexec(reexecCtx)
+		cancel()
+		if err != nil {
+			log.Error("re-exec failed, still serving", logger.Err(err))
+			continue
+		}
+		stop()
+		return
+	}
+}
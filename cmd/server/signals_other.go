@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// reexecSignals: re-exec is only supported on unix.
+var reexecSignals []os.Signal
@@ -0,0 +1,11 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// reexecSignals trigger a graceful re-exec of the server.
+var reexecSignals = []os.Signal{syscall.SIGUSR2}
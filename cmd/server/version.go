@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+func runVersion(args []string) error {
+	fs := newFlagSet("version")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return errors.New("build information is not available")
+	}
+
+	settings := map[string]string{}
+	for _, s := range info.Settings {
+		settings[s.Key] = s.Value
+	}
+
+	fmt.Printf("%s %s\n", info.Main.Path, info.Main.Version)
+	fmt.Printf("go: %s\n", info.GoVersion)
+	if revision := settings["vcs.revision"]; revision != "" {
+		if settings["vcs.modified"] == "true" {
+			revision += " (modified)"
+		}
+		fmt.Printf("revision: %s\n", revision)
+	}
+	if built := settings["vcs.time"]; built != "" {
+		fmt.Printf("commit time: %s\n", built)
+	}
+	fmt.Printf("platform: %s/%s\n", settings["GOOS"], settings["GOARCH"])
+	return nil
+}
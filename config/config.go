@@ -2,37 +2,187 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/MagicRodri/grpc_with_go/pkg/logger"
-	"github.com/go-playground/validator/v10"
+	"github.com/MagicRodri/grpc_with_go/pkg/schema"
+	"github.com/MagicRodri/grpc_with_go/pkg/validation"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
 type GrpcConfig struct {
-	Address string `mapstructure:"address" validate:"required"`
+	Address         string           `mapstructure:"address" validate:"required,hostport" default:"localhost:50051" desc:"Address the gRPC server listens on when no listeners are configured"`
+	Listeners       []ListenerConfig `mapstructure:"listeners" validate:"dive" desc:"Addresses the gRPC server listens on, replacing address"`
+	HTTP            HTTPConfig       `mapstructure:"http" desc:"HTTP endpoints served on the gRPC listeners"`
+	ShutdownTimeout time.Duration    `mapstructure:"shutdown_timeout" validate:"duration" default:"30s" desc:"Time given to in-flight calls and HTTP requests on shutdown before connections are closed"`
+}
+
+// HTTPConfig multiplexes HTTP/1.1 and h2c requests with gRPC on the same
+// listeners: HTTP/2 requests with an application/grpc content type go to the
+// gRPC server, everything else to the HTTP handlers.
+type HTTPConfig struct {
+	Enabled    bool   `mapstructure:"enabled" desc:"Serve HTTP next to gRPC on every listener"`
+	HealthPath string `mapstructure:"health_path" validate:"omitempty,startswith=/" default:"/healthz" desc:"Path of the health check, empty disables it"`
+	AdminPath  string `mapstructure:"admin_path" validate:"omitempty,startswith=/" default:"/admin" desc:"Prefix of the admin endpoints such as /admin/log-levels, empty disables them"`
+	Gateway    bool   `mapstructure:"gateway" default:"true" desc:"Serve the REST/JSON gateway under /v1/ and its OpenAPI description at /openapi.json"`
+}
+
+// ListenerConfig is one address the gRPC server accepts connections on.
+// Address is host:port, tcp://host:port, unix:///path/to.sock or fd:// with the
+// number or name of a socket passed with LISTEN_FDS; Mode sets the permissions
+// of a unix socket file, written as an octal string such as "0660" or as a YAML
+// octal integer such as 0660. The admin API is only served on listeners with
+// Admin set, such as a unix socket restricted by its mode.
+type ListenerConfig struct {
+	Address string      `mapstructure:"address" validate:"required,listen_address" desc:"host:port, tcp://host:port, unix:///path/to.sock or fd://3 for a socket passed with LISTEN_FDS"`
+	Mode    os.FileMode `mapstructure:"mode" validate:"omitempty,file_mode" desc:"Permissions of the unix socket file, an octal string such as \"0660\" or a YAML octal integer"`
+	Admin   bool        `mapstructure:"admin" desc:"Serve the admin API (log levels, log streaming) on this listener; no listener serves it by default"`
+}
+
+// Endpoints returns the configured listeners, or address when there are none.
+func (c *GrpcConfig) Endpoints() []ListenerConfig {
+	if len(c.Listeners) > 0 {
+		return c.Listeners
+	}
+	return []ListenerConfig{{Address: c.Address}}
 }
 
 type Config struct {
-	GRPC   GrpcConfig    `mapstructure:"grpc" validate:"required"`
-	Logger logger.Config `mapstructure:"logger" validate:"required"`
+	GRPC   GrpcConfig    `mapstructure:"grpc" validate:"required" desc:"gRPC server"`
+	Logger logger.Config `mapstructure:"logger" validate:"required" desc:"Logging"`
+
+	// secrets maps the keys resolved from secret references to the references
+	secrets map[string]string
+}
+
+// decodeHook extends the default hooks of viper with file modes.
+var decodeHook = mapstructure.ComposeDecodeHookFunc(
+	fileModeHook,
+	mapstructure.StringToTimeDurationHookFunc(),
+	mapstructure.StringToSliceHookFunc(","),
+)
+
+var fileModeType = reflect.TypeOf(os.FileMode(0))
+
+// fileModeHook reads a file mode string such as "0660" as octal. Integers are
+// taken as is, so an unquoted YAML 0660 keeps its octal value.
+func fileModeHook(_, to reflect.Type, data any) (any, error) {
+	s, ok := data.(string)
+	if !ok || to != fileModeType {
+		return data, nil
+	}
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file mode %q, want octal permissions such as 0660", s)
+	}
+	return os.FileMode(mode), nil
+}
+
+// keyDelimiter keeps dotted map keys such as component names ("grpc.server") intact
+const keyDelimiter = "::"
+
+type loadOptions struct {
+	envPrefix string
+	profile   string
+	flags     *pflag.FlagSet
 }
 
-func LoadConfig(path string) (*Config, error) {
-	viper.SetConfigFile(path)
+// Option customizes LoadConfig.
+type Option func(*loadOptions)
 
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("error reading config file: %w", err)
+// WithEnvPrefix changes the prefix of the environment variables, APP by default.
+func WithEnvPrefix(prefix string) Option {
+	return func(o *loadOptions) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithProfile merges the profile overlay of the config file on top of it,
+// e.g. config.prod.yaml over config.yaml for profile "prod".
+func WithProfile(profile string) Option {
+	return func(o *loadOptions) {
+		o.profile = profile
+	}
+}
+
+// WithFlags applies the flags registered with BindFlags that were set on the command line.
+func WithFlags(fs *pflag.FlagSet) Option {
+	return func(o *loadOptions) {
+		o.flags = fs
+	}
+}
+
+// LoadConfig reads the configuration with the following precedence, highest first:
+//
+//  1. command line flags registered with BindFlags (see WithFlags)
+//  2. environment variables, e.g. APP_GRPC_ADDRESS for grpc.address
+//  3. the profile overlay of the config file (see WithProfile)
+//  4. the config file at path and the files it includes, skipped when path is empty
+//  5. the default tags of the config structs
+//
+// Files are deep-merged key by key; lists replace the previous ones.
+// String values of the form scheme://ref, e.g. file:///run/secrets/token or
+// env://TOKEN, are then replaced by the secret they reference (see
+// RegisterSecretResolver).
+// Every call uses its own viper instance, so several configurations can be
+// loaded in one process.
+func LoadConfig(path string, opts ...Option) (*Config, error) {
+	config, _, err := ResolveConfig(path, opts...)
+	return config, err
+}
+
+// ResolveConfig loads the configuration like LoadConfig and also returns every
+// effective setting with its source.
+func ResolveConfig(path string, opts ...Option) (*Config, []Setting, error) {
+	o := &loadOptions{envPrefix: DefaultEnvPrefix}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	v := viper.NewWithOptions(viper.KeyDelimiter(keyDelimiter))
+	var files *layers
+	if path != "" {
+		var err error
+		if files, err = loadLayers(path, o.profile); err != nil {
+			return nil, nil, err
+		}
+		if err := v.MergeConfigMap(files.values); err != nil {
+			return nil, nil, fmt.Errorf("error merging config file: %w", err)
+		}
+	}
+
+	if err := bindOverrides(v, o.envPrefix, o.flags); err != nil {
+		return nil, nil, err
+	}
+
+	values := v.AllSettings()
+	schema.ApplyDefaults(reflect.TypeOf(Config{}), values)
+	secrets, err := resolveSecrets(values)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolved := viper.NewWithOptions(viper.KeyDelimiter(keyDelimiter))
+	if err := resolved.MergeConfigMap(values); err != nil {
+		return nil, nil, fmt.Errorf("error merging resolved secrets: %w", err)
 	}
 
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
-		return nil, fmt.Errorf("error unmarshalling config: %w", err)
+	if err := resolved.Unmarshal(&config, viper.DecodeHook(decodeHook)); err != nil {
+		return nil, nil, fmt.Errorf("error unmarshalling config: %w", err)
 	}
 
-	validate := validator.New(validator.WithRequiredStructEnabled())
-	if err := validate.Struct(&config); err != nil {
-		return nil, fmt.Errorf("failed to validate config file %s: %w", path, err)
+	if err := validation.Validate(&config); err != nil {
+		if path == "" {
+			return nil, nil, fmt.Errorf("invalid config: %w", err)
+		}
+		return nil, nil, fmt.Errorf("invalid config %s: %w", path, err)
 	}
 
-	return &config, nil
+	config.secrets = secrets
+	return &config, settings(values, secrets, files, o.envPrefix, o.flags), nil
 }
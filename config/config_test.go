@@ -0,0 +1,82 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/validation"
+)
+
+func TestLoadConfigListenerMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    os.FileMode
+		invalid bool
+	}{
+		{name: "quoted", file: "config.yaml", content: "mode: \"0660\"", want: 0o660},
+		{name: "yaml octal", file: "config.yaml", content: "mode: 0660", want: 0o660},
+		{name: "json string", file: "config.json", content: `"mode": "0600"`, want: 0o600},
+		{name: "json decimal", file: "config.json", content: `"mode": 660`, invalid: true},
+		{name: "not octal", file: "config.yaml", content: "mode: \"0689\"", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "grpc:\n  listeners:\n    - address: unix:///tmp/test.sock\n      " + tt.content + "\n"
+			if filepath.Ext(tt.file) == ".json" {
+				content = `{"grpc": {"listeners": [{"address": "unix:///tmp/test.sock", ` + tt.content + `}]}}`
+			}
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := LoadConfig(path)
+			if tt.invalid {
+				if err == nil {
+					t.Fatalf("mode = %#o, want an error", cfg.GRPC.Listeners[0].Mode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mode := cfg.GRPC.Listeners[0].Mode; mode != tt.want {
+				t.Fatalf("mode = %#o, want %#o", mode, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigErrorPaths(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config.yaml": `
+grpc:
+  address: localhost
+logger:
+  level: loud
+  sinks:
+    - output: kafka
+    - output: syslog
+`})
+
+	_, err := LoadConfig(filepath.Join(dir, "config.yaml"))
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("LoadConfig = %v, want validation errors", err)
+	}
+
+	var paths []string
+	for _, fe := range errs {
+		paths = append(paths, fe.Path)
+	}
+	slices.Sort(paths)
+	want := []string{"grpc.address", "logger.level", "logger.sinks[0].output", "logger.sinks[1].syslog.address"}
+	if !slices.Equal(paths, want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/pflag"
+)
+
+// Setting is a resolved configuration value together with where it came from:
+// a flag, an environment variable, a config file or the default. Values
+// resolved from secret references and HTTP headers are redacted.
+type Setting struct {
+	Key    string
+	Value  any
+	Source string
+}
+
+const defaultSource = "default"
+
+// settings flattens the resolved values and attributes each of them to its source.
+func settings(values map[string]any, secrets map[string]string, files *layers, envPrefix string, fs *pflag.FlagSet) []Setting {
+	// overrides of maps and lists also hold for their elements
+	overrides := map[string]string{}
+	var jsonKeys []string
+	for _, key := range configKeys(reflect.TypeOf(Config{}), nil) {
+		if key.json() {
+			jsonKeys = append(jsonKeys, key.name())
+		}
+		name := key.name()
+		if fs != nil {
+			if flag := fs.Lookup(name); flag != nil && flag.Changed {
+				overrides[name] = "flag --" + name
+				continue
+			}
+		}
+		if _, ok := os.LookupEnv(key.envName(envPrefix)); ok {
+			overrides[name] = "env " + key.envName(envPrefix)
+		}
+	}
+
+	var result []Setting
+	var walk func(prefix string, value any)
+	walk = func(prefix string, value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, item := range v {
+				walk(joinPath(prefix, key), item)
+			}
+		case []any:
+			if len(v) == 0 {
+				result = append(result, Setting{Key: prefix, Value: v})
+			}
+			for i, item := range v {
+				walk(fmt.Sprintf("%s[%d]", prefix, i), item)
+			}
+		default:
+			result = append(result, Setting{Key: prefix, Value: v})
+		}
+	}
+	walk("", values)
+
+	for i := range result {
+		key := result[i].Key
+		switch {
+		case overrides[key] != "":
+			result[i].Source = overrides[key]
+		case overrideOf(key, jsonKeys, overrides) != "":
+			result[i].Source = overrideOf(key, jsonKeys, overrides)
+		case files != nil && files.sources[key] != "":
+			result[i].Source = files.sources[key]
+		default:
+			result[i].Source = defaultSource
+		}
+		if ref := secretRef(key, secrets); ref != "" {
+			result[i].Value = redacted
+			result[i].Source += ", secret " + ref
+		}
+		if strings.Contains(key, ".headers.") {
+			result[i].Value = redacted
+		}
+	}
+
+	slices.SortFunc(result, func(a, b Setting) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return result
+}
+
+// overrideOf returns the override of the map or list that contains key.
+func overrideOf(key string, jsonKeys []string, overrides map[string]string) string {
+	for _, name := range jsonKeys {
+		if overrides[name] != "" && (strings.HasPrefix(key, name+".") || strings.HasPrefix(key, name+"[")) {
+			return overrides[name]
+		}
+	}
+	return ""
+}
+
+// WriteSettings prints one "key = value  # source" line per setting.
+func WriteSettings(w io.Writer, settings []Setting) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, s := range settings {
+		if _, err := fmt.Fprintf(tw, "%s = %v\t# %s\n", s.Key, s.Value, s.Source); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// secretRef returns the reference key or, for string lists, one of its elements was resolved from.
+func secretRef(key string, secrets map[string]string) string {
+	if ref, ok := secrets[key]; ok {
+		return ref
+	}
+	for path, ref := range secrets {
+		if strings.HasPrefix(path, key+"[") {
+			return ref
+		}
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// includeKey lists files merged before the content of the file declaring it.
+// Relative paths are resolved against the directory of that file.
+const includeKey = "include"
+
+// layers is the deep-merged content of the config files together with the
+// file every leaf value was taken from.
+type layers struct {
+	values  map[string]any
+	sources map[string]string
+}
+
+// profilePath returns the overlay of path for profile, e.g. config.prod.yaml for config.yaml.
+func profilePath(path, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
+
+// loadLayers reads path and, when profile is set, its profile overlay.
+func loadLayers(path, profile string) (*layers, error) {
+	l := &layers{values: map[string]any{}, sources: map[string]string{}}
+	if err := l.load(path, nil); err != nil {
+		return nil, err
+	}
+	if profile != "" {
+		overlay := profilePath(path, profile)
+		if err := l.load(overlay, nil); err != nil {
+			return nil, fmt.Errorf("profile %s: %w", profile, err)
+		}
+	}
+	return l, nil
+}
+
+func (l *layers) load(path string, stack []string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("error resolving config file %s: %w", path, err)
+	}
+	if slices.Contains(stack, abs) {
+		return fmt.Errorf("config include cycle: %s", strings.Join(append(stack, abs), " -> "))
+	}
+
+	content, err := readFile(path)
+	if err != nil {
+		return err
+	}
+
+	includes, err := includeList(content[includeKey])
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	delete(content, includeKey)
+
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		if err := l.load(include, append(stack, abs)); err != nil {
+			return err
+		}
+	}
+
+	mergeMaps(l.values, content, "", path, l.sources)
+	return nil
+}
+
+// readFile parses path in the format given by its extension: yaml, json, toml
+// or any other format supported by viper.
+func readFile(path string) (map[string]any, error) {
+	v := viper.NewWithOptions(viper.KeyDelimiter(keyDelimiter))
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", path, err)
+	}
+	return v.AllSettings(), nil
+}
+
+func includeList(value any) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []any:
+		includes := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a list of file paths", includeKey)
+			}
+			includes = append(includes, s)
+		}
+		return includes, nil
+	default:
+		return nil, fmt.Errorf("%s must be a file path or a list of file paths", includeKey)
+	}
+}
+
+// mergeMaps deep-merges src into dst. Maps are merged key by key; lists and
+// any other values replace the previous ones, so an overlay listing sinks
+// replaces all of them.
+func mergeMaps(dst, src map[string]any, prefix, source string, sources map[string]string) {
+	for key, value := range src {
+		key = strings.ToLower(key)
+		path := joinPath(prefix, key)
+		dst[key] = mergeValue(dst[key], value, path, source, sources)
+	}
+}
+
+func mergeValue(dst, src any, path, source string, sources map[string]string) any {
+	switch s := src.(type) {
+	case map[string]any:
+		if d, ok := dst.(map[string]any); ok {
+			mergeMaps(d, s, path, source, sources)
+			return d
+		}
+		forgetSources(path, sources)
+		d := map[string]any{}
+		mergeMaps(d, s, path, source, sources)
+		return d
+	case []any:
+		forgetSources(path, sources)
+		list := make([]any, len(s))
+		for i, item := range s {
+			list[i] = mergeValue(nil, item, fmt.Sprintf("%s[%d]", path, i), source, sources)
+		}
+		return list
+	default:
+		forgetSources(path, sources)
+		sources[path] = source
+		return src
+	}
+}
+
+// forgetSources drops the sources recorded for path and everything below it.
+func forgetSources(path string, sources map[string]string) {
+	for key := range sources {
+		if key == path || strings.HasPrefix(key, path+".") || strings.HasPrefix(key, path+"[") {
+			delete(sources, key)
+		}
+	}
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadLayersFormats(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": "include: [base.json, extra.toml]\ngrpc:\n  address: yaml\n",
+		"base.json":   `{"grpc": {"address": "json", "http": {"enabled": true}}}`,
+		"extra.toml":  "[logger]\nlevel = \"debug\"\n",
+	})
+
+	l, err := loadLayers(filepath.Join(dir, "config.yaml"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"grpc.address":      "config.yaml",
+		"grpc.http.enabled": "base.json",
+		"logger.level":      "extra.toml",
+	}
+	for key, file := range want {
+		if got := filepath.Base(l.sources[key]); got != file {
+			t.Errorf("source of %s = %s, want %s", key, got, file)
+		}
+	}
+	if address := l.values["grpc"].(map[string]any)["address"]; address != "yaml" {
+		t.Errorf("grpc.address = %v, want yaml", address)
+	}
+}
+
+func TestLoadLayersReplacesLists(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml":      "logger:\n  sinks:\n    - output: stdout\n    - output: file\n      path: app.log\n",
+		"config.prod.yaml": "logger:\n  sinks:\n    - output: stderr\n",
+	})
+
+	l, err := loadLayers(filepath.Join(dir, "config.yaml"), "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sinks := l.values["logger"].(map[string]any)["sinks"]
+	want := []any{map[string]any{"output": "stderr"}}
+	if !reflect.DeepEqual(sinks, want) {
+		t.Fatalf("sinks = %v, want %v", sinks, want)
+	}
+	if _, ok := l.sources["logger.sinks[1].path"]; ok {
+		t.Fatal("the source of the replaced sink is still recorded")
+	}
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/schema"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// DefaultEnvPrefix is prepended to the environment variable of every key,
+// e.g. grpc.address is read from APP_GRPC_ADDRESS.
+const DefaultEnvPrefix = "APP"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// configKey is a leaf of the configuration that can be set from a flag or an
+// environment variable. Maps and lists of structs are set as JSON.
+type configKey struct {
+	path []string
+	typ  reflect.Type
+	def  string
+}
+
+// json reports whether the key takes a JSON object or array.
+func (k configKey) json() bool {
+	switch k.typ.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Slice:
+		return k.typ.Elem().Kind() != reflect.String
+	default:
+		return false
+	}
+}
+
+// name returns the dotted key used for flags and documentation, e.g. grpc.address.
+func (k configKey) name() string {
+	return strings.Join(k.path, ".")
+}
+
+func (k configKey) viperKey() string {
+	return strings.Join(k.path, keyDelimiter)
+}
+
+func (k configKey) envName(prefix string) string {
+	name := strings.ToUpper(strings.Join(k.path, "_"))
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// configKeys lists the settable keys of t following the mapstructure tags.
+func configKeys(t reflect.Type, prefix []string) []configKey {
+	var keys []configKey
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("mapstructure")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && strings.Contains(opts, "squash") {
+			keys = append(keys, configKeys(field.Type, prefix)...)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		path := append(append([]string(nil), prefix...), name)
+		def := field.Tag.Get("default")
+
+		switch {
+		case field.Type == durationType:
+			keys = append(keys, configKey{path: path, typ: field.Type, def: def})
+		case field.Type.Kind() == reflect.Struct:
+			keys = append(keys, configKeys(field.Type, path)...)
+		default:
+			keys = append(keys, configKey{path: path, typ: field.Type, def: def})
+		}
+	}
+	return keys
+}
+
+// BindFlags registers a flag named after every settable key of Config,
+// e.g. --grpc.address or --logger.level. Pass the same flag set to WithFlags.
+// Flag defaults only document the default tags: a flag overrides the file
+// only when it is set on the command line. Maps and lists of structs, such as
+// --grpc.listeners, take JSON.
+func BindFlags(fs *pflag.FlagSet) {
+	for _, key := range configKeys(reflect.TypeOf(Config{}), nil) {
+		name := key.name()
+		usage := fmt.Sprintf("overrides %s (env %s)", name, key.envName(DefaultEnvPrefix))
+		if key.json() {
+			fs.String(name, "", fmt.Sprintf("overrides %s as JSON (env %s)", name, key.envName(DefaultEnvPrefix)))
+			continue
+		}
+		def := reflect.Zero(key.typ).Interface()
+		if key.def != "" {
+			if value, err := schema.ParseDefault(key.typ, key.def); err == nil {
+				def = value
+			}
+		}
+
+		switch {
+		case key.typ == durationType:
+			fs.Duration(name, def.(time.Duration), usage)
+		case key.typ.Kind() == reflect.Bool:
+			fs.Bool(name, def.(bool), usage)
+		case key.typ.Kind() == reflect.Int:
+			fs.Int(name, int(reflect.ValueOf(def).Int()), usage)
+		case key.typ.Kind() == reflect.Int64:
+			fs.Int64(name, reflect.ValueOf(def).Int(), usage)
+		case key.typ.Kind() == reflect.Slice:
+			fs.StringSlice(name, reflect.ValueOf(def).Convert(reflect.TypeOf([]string(nil))).Interface().([]string), usage)
+		default:
+			fs.String(name, reflect.ValueOf(def).String(), usage)
+		}
+	}
+}
+
+// bindOverrides binds every settable key to its environment variable and,
+// when fs is not nil, to the flag registered by BindFlags.
+func bindOverrides(v *viper.Viper, envPrefix string, fs *pflag.FlagSet) error {
+	for _, key := range configKeys(reflect.TypeOf(Config{}), nil) {
+		if key.json() {
+			if err := setJSON(v, key, envPrefix, fs); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := v.BindEnv(key.viperKey(), key.envName(envPrefix)); err != nil {
+			return fmt.Errorf("failed to bind env for %s: %w", key.name(), err)
+		}
+		if fs == nil {
+			continue
+		}
+		// only flags set on the command line take precedence over the file
+		if flag := fs.Lookup(key.name()); flag != nil && flag.Changed {
+			if err := v.BindPFlag(key.viperKey(), flag); err != nil {
+				return fmt.Errorf("failed to bind flag --%s: %w", key.name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// setJSON sets a map or list key from the JSON in its flag or, when the flag
+// is not set, its environment variable. A list replaces the one of the files,
+// a map is merged with them key by key.
+func setJSON(v *viper.Viper, key configKey, envPrefix string, fs *pflag.FlagSet) error {
+	var raw, source string
+	if value, ok := os.LookupEnv(key.envName(envPrefix)); ok {
+		raw, source = value, key.envName(envPrefix)
+	}
+	if fs != nil {
+		if flag := fs.Lookup(key.name()); flag != nil && flag.Changed {
+			raw, source = flag.Value.String(), "--"+key.name()
+		}
+	}
+	if source == "" {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return fmt.Errorf("invalid JSON in %s: %w", source, err)
+	}
+	switch value.(type) {
+	case map[string]any:
+		if key.typ.Kind() != reflect.Map {
+			return fmt.Errorf("%s: %s takes a JSON array", source, key.name())
+		}
+	case []any:
+		if key.typ.Kind() != reflect.Slice {
+			return fmt.Errorf("%s: %s takes a JSON object", source, key.name())
+		}
+	default:
+		return fmt.Errorf("%s: %s takes a JSON object or array", source, key.name())
+	}
+	v.Set(key.viperKey(), value)
+	return nil
+}
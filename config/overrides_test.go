@@ -0,0 +1,170 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// sourceOf returns the source of key in settings.
+func sourceOf(settings []Setting, key string) string {
+	for _, s := range settings {
+		if s.Key == key {
+			return s.Source
+		}
+	}
+	return ""
+}
+
+func TestOverridePrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       bool
+		profile    bool
+		env        bool
+		flag       bool
+		want       string
+		wantSource string
+	}{
+		{name: "default", want: "localhost:50051", wantSource: defaultSource},
+		{name: "file", file: true, want: "file:1", wantSource: "config.yaml"},
+		{name: "profile", file: true, profile: true, want: "profile:1", wantSource: "config.prod.yaml"},
+		{name: "env", file: true, profile: true, env: true, want: "env:1", wantSource: "env APP_GRPC_ADDRESS"},
+		{name: "flag", file: true, profile: true, env: true, flag: true, want: "flag:1", wantSource: "flag --grpc.address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"config.yaml": "logger:\n  level: info\n"}
+			if tt.file {
+				files["config.yaml"] = "grpc:\n  address: file:1\n"
+			}
+			if tt.profile {
+				files["config.prod.yaml"] = "grpc:\n  address: profile:1\n"
+			}
+			dir := writeFiles(t, files)
+			if tt.env {
+				t.Setenv("APP_GRPC_ADDRESS", "env:1")
+			}
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			BindFlags(fs)
+			if tt.flag {
+				if err := fs.Parse([]string{"--grpc.address", "flag:1"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			opts := []Option{WithFlags(fs)}
+			if tt.profile {
+				opts = append(opts, WithProfile("prod"))
+			}
+
+			cfg, settings, err := ResolveConfig(filepath.Join(dir, "config.yaml"), opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.GRPC.Address != tt.want {
+				t.Fatalf("grpc.address = %s, want %s", cfg.GRPC.Address, tt.want)
+			}
+			if source := filepath.Base(sourceOf(settings, "grpc.address")); source != tt.wantSource {
+				t.Fatalf("source = %s, want %s", source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestDelimiterKeepsDottedKeys(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": "logger:\n  components:\n    grpc.server: debug\n    manager: warn\n",
+	})
+	t.Setenv("APP_LOGGER_COMPONENTS", `{"client.status": "error", "manager": "info"}`)
+
+	cfg, settings, err := ResolveConfig(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"grpc.server": "debug", "manager": "info", "client.status": "error"}
+	if len(cfg.Logger.Components) != len(want) {
+		t.Fatalf("components = %v, want %v", cfg.Logger.Components, want)
+	}
+	for name, level := range want {
+		if cfg.Logger.Components[name] != level {
+			t.Fatalf("components = %v, want %v", cfg.Logger.Components, want)
+		}
+	}
+	if source := sourceOf(settings, "logger.components.client.status"); source != "env APP_LOGGER_COMPONENTS" {
+		t.Fatalf("source of client.status = %q", source)
+	}
+}
+
+func TestJSONOverridesReplaceLists(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": "grpc:\n  listeners:\n    - address: localhost:1\n    - address: localhost:2\n",
+	})
+	path := filepath.Join(dir, "config.yaml")
+	t.Setenv("APP_GRPC_LISTENERS", `[{"address": "unix:///tmp/app.sock", "mode": "0660", "admin": true}]`)
+
+	cfg, settings, err := ResolveConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listeners := cfg.GRPC.Listeners
+	if len(listeners) != 1 || listeners[0].Address != "unix:///tmp/app.sock" || listeners[0].Mode != 0o660 || !listeners[0].Admin {
+		t.Fatalf("listeners = %+v, want the env listener only", listeners)
+	}
+	if source := sourceOf(settings, "grpc.listeners[0].address"); source != "env APP_GRPC_LISTENERS" {
+		t.Fatalf("source = %q, want the env variable", source)
+	}
+
+	// the flag takes precedence over the environment
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	BindFlags(fs)
+	if err := fs.Parse([]string{`--grpc.listeners=[{"address": "localhost:3"}]`}); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadConfig(path, WithFlags(fs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listeners := cfg.GRPC.Listeners; len(listeners) != 1 || listeners[0].Address != "localhost:3" {
+		t.Fatalf("listeners = %+v, want the flag listener", listeners)
+	}
+}
+
+func TestJSONOverridesInvalid(t *testing.T) {
+	tests := []struct {
+		env   string
+		value string
+		want  string
+	}{
+		{env: "APP_GRPC_LISTENERS", value: `[{"address": `, want: "invalid JSON in APP_GRPC_LISTENERS"},
+		{env: "APP_GRPC_LISTENERS", value: `{"address": "localhost:1"}`, want: "takes a JSON array"},
+		{env: "APP_LOGGER_COMPONENTS", value: `["debug"]`, want: "takes a JSON object"},
+		{env: "APP_LOGGER_SINKS", value: `"stdout"`, want: "takes a JSON object or array"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env+" "+tt.value, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			_, err := LoadConfig("")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("LoadConfig = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindFlagsRegistersJSONKeys(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	BindFlags(fs)
+	for _, name := range []string{"grpc.listeners", "logger.sinks", "logger.components", "logger.http.headers"} {
+		flag := fs.Lookup(name)
+		if flag == nil {
+			t.Fatalf("flag --%s is not registered", name)
+		}
+		if !strings.Contains(flag.Usage, "JSON") {
+			t.Fatalf("usage of --%s = %q, want a mention of JSON", name, flag.Usage)
+		}
+	}
+}
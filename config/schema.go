@@ -0,0 +1,32 @@
+package config
+
+import (
+	"bytes"
+	"reflect"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/schema"
+)
+
+// JSONSchema describes the config file, including the include key handled by the loader.
+func JSONSchema() *schema.Schema {
+	s := schema.Generate(reflect.TypeOf(Config{}), "Server configuration")
+	s.Properties[includeKey] = &schema.Schema{
+		Description: "Files merged before this one, relative to its directory",
+		OneOf: []*schema.Schema{
+			{Type: "string"},
+			{Type: "array", Items: &schema.Schema{Type: "string"}},
+		},
+	}
+	return s
+}
+
+// Example returns an annotated config file with every key and its default.
+// schemaPath, when set, is referenced for editors using yaml-language-server.
+func Example(schemaPath string) []byte {
+	var b bytes.Buffer
+	if schemaPath != "" {
+		b.WriteString("# yaml-language-server: $schema=" + schemaPath + "\n\n")
+	}
+	b.Write(schema.Example(reflect.TypeOf(Config{})))
+	return b.Bytes()
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGeneratedFilesUpToDate fails when the committed schema or example differ
+// from the config structs; run "make schema" to regenerate them.
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	schema, err := json.MarshalIndent(JSONSchema(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string][]byte{
+		"config.schema.json":  append(schema, '\n'),
+		"config.example.yaml": Example("config.schema.json"),
+	} {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date, run make schema", path)
+		}
+	}
+}
+
+func TestExampleLoadsAsDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, Example(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fromExample, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig of the example: %v", err)
+	}
+	defaults, err := LoadConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// compared as printed: the example sets empty lists and maps, the defaults leave them nil
+	if got, want := fmt.Sprintf("%+v", fromExample), fmt.Sprintf("%+v", defaults); got != want {
+		t.Fatalf("example = %s\nwant the defaults %s", got, want)
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces secret values whenever the configuration is printed or logged.
+const redacted = "[REDACTED]"
+
+// SecretResolver returns the secret a reference points to. It receives the
+// reference without its scheme, e.g. /run/secrets/token for file:///run/secrets/token.
+type SecretResolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// SecretResolverFunc adapts a function to SecretResolver.
+type SecretResolverFunc func(ref string) (string, error)
+
+func (f SecretResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+var (
+	resolversMutex sync.RWMutex
+	resolvers      = map[string]SecretResolver{
+		"file": SecretResolverFunc(resolveFile),
+		"env":  SecretResolverFunc(resolveEnv),
+	}
+)
+
+// RegisterSecretResolver makes string values of the form scheme://ref resolve
+// through r at load time, replacing any resolver registered for scheme.
+// The file and env schemes are registered by default.
+func RegisterSecretResolver(scheme string, r SecretResolver) {
+	resolversMutex.Lock()
+	defer resolversMutex.Unlock()
+	resolvers[strings.ToLower(scheme)] = r
+}
+
+func lookupResolver(value string) (SecretResolver, string, bool) {
+	scheme, ref, ok := strings.Cut(value, "://")
+	if !ok {
+		return nil, "", false
+	}
+	resolversMutex.RLock()
+	defer resolversMutex.RUnlock()
+	r, ok := resolvers[strings.ToLower(scheme)]
+	return r, ref, ok
+}
+
+// resolveFile reads a secret file such as a Docker or Kubernetes mounted secret,
+// without the trailing newline most editors add.
+func resolveFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func resolveEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// resolveSecrets replaces the secret references in values in place and returns
+// the reference of every resolved key.
+func resolveSecrets(values map[string]any) (map[string]string, error) {
+	secrets := map[string]string{}
+	var walk func(path string, value any) (any, error)
+	walk = func(path string, value any) (any, error) {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, item := range v {
+				resolved, err := walk(joinPath(path, key), item)
+				if err != nil {
+					return nil, err
+				}
+				v[key] = resolved
+			}
+		case []any:
+			for i, item := range v {
+				resolved, err := walk(fmt.Sprintf("%s[%d]", path, i), item)
+				if err != nil {
+					return nil, err
+				}
+				v[i] = resolved
+			}
+		case []string:
+			for i, item := range v {
+				resolved, err := walk(fmt.Sprintf("%s[%d]", path, i), item)
+				if err != nil {
+					return nil, err
+				}
+				v[i] = resolved.(string)
+			}
+		case string:
+			r, ref, ok := lookupResolver(v)
+			if !ok {
+				return v, nil
+			}
+			secret, err := r.Resolve(ref)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve secret %s for %s: %w", v, path, err)
+			}
+			secrets[path] = v
+			return secret, nil
+		}
+		return value, nil
+	}
+	_, err := walk("", values)
+	return secrets, err
+}
+
+// LogValue logs the configuration with the values resolved from secret
+// references and the values of HTTP headers, which usually carry credentials,
+// replaced by [REDACTED].
+func (c Config) LogValue() slog.Value {
+	return logValue(reflect.ValueOf(c), "", c.secrets)
+}
+
+func logValue(v reflect.Value, path string, secrets map[string]string) slog.Value {
+	if secretRef(path, secrets) != "" {
+		return slog.StringValue(redacted)
+	}
+
+	switch {
+	case v.Type() == durationType:
+		return slog.DurationValue(time.Duration(v.Int()))
+	case v.Kind() == reflect.Struct:
+		var attrs []slog.Attr
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if name == "-" {
+				continue
+			}
+			if field.Anonymous && strings.Contains(opts, "squash") {
+				// a group without a key is inlined
+				attrs = append(attrs, slog.Attr{Value: logValue(v.Field(i), path, secrets)})
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			attrs = append(attrs, slog.Attr{Key: name, Value: logValue(v.Field(i), joinPath(path, name), secrets)})
+		}
+		return slog.GroupValue(attrs...)
+	case v.Kind() == reflect.Map:
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		attrs := make([]slog.Attr, 0, len(keys))
+		for _, key := range keys {
+			value := slog.StringValue(redacted)
+			if !strings.HasSuffix(path, ".headers") {
+				value = logValue(v.MapIndex(key), joinPath(path, strings.ToLower(key.String())), secrets)
+			}
+			attrs = append(attrs, slog.Attr{Key: key.String(), Value: value})
+		}
+		return slog.GroupValue(attrs...)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct:
+		attrs := make([]slog.Attr, v.Len())
+		for i := range attrs {
+			attrs[i] = slog.Attr{Key: strconv.Itoa(i), Value: logValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), secrets)}
+		}
+		return slog.GroupValue(attrs...)
+	default:
+		return slog.AnyValue(v.Interface())
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigLogValueRedactsSecrets(t *testing.T) {
+	t.Setenv("TEST_COLLECTOR_URL", "http://collector.internal/logs")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	content := `logger:
+  output: http
+  http:
+    url: env://TEST_COLLECTOR_URL
+    headers:
+      Authorization: Bearer literal-token
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Logger.HTTP.URL != "http://collector.internal/logs" {
+		t.Fatalf("url = %q, want the resolved secret", cfg.Logger.HTTP.URL)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("loaded", "config", cfg)
+	out := buf.String()
+	for _, leaked := range []string{"collector.internal", "literal-token"} {
+		if strings.Contains(out, leaked) {
+			t.Fatalf("log output contains %q: %s", leaked, out)
+		}
+	}
+	for _, want := range []string{"config.logger.http.url=[REDACTED]", "config.logger.http.headers.authorization=[REDACTED]", "config.grpc.address=localhost:50051"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("log output lacks %q: %s", want, out)
+		}
+	}
+}
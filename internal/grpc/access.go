@@ -0,0 +1,124 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/admin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// The admin API (log levels and log streaming) is only served on connections
+// accepted by listeners with admin enabled in their ListenerConfig. Plain gRPC
+// connections carry the mark in their peer AuthInfo, set by adminCredentials;
+// connections served through net/http carry it in the request context.
+
+var errAdminDenied = status.Error(codes.PermissionDenied, "the admin API is not enabled on this listener")
+
+// adminListener marks every accepted connection as an admin connection.
+type adminListener struct {
+	net.Listener
+}
+
+func (l adminListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &adminConn{Conn: conn}, nil
+}
+
+type adminConn struct {
+	net.Conn
+}
+
+// adminCredentials are insecure credentials that record in the peer whether
+// the connection was accepted by an admin listener.
+type adminCredentials struct {
+	credentials.TransportCredentials
+}
+
+func newAdminCredentials() credentials.TransportCredentials {
+	return adminCredentials{TransportCredentials: insecure.NewCredentials()}
+}
+
+func (c adminCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, info, err := c.TransportCredentials.ServerHandshake(rawConn)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := rawConn.(*adminConn); ok {
+		info = adminAuthInfo{CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity}}
+	}
+	return conn, info, nil
+}
+
+func (c adminCredentials) Clone() credentials.TransportCredentials {
+	return adminCredentials{TransportCredentials: c.TransportCredentials.Clone()}
+}
+
+type adminAuthInfo struct {
+	credentials.CommonAuthInfo
+}
+
+func (adminAuthInfo) AuthType() string {
+	return "insecure"
+}
+
+type adminContextKey struct{}
+
+// adminConnContext marks the requests of admin connections served through net/http.
+func adminConnContext(ctx context.Context, conn net.Conn) context.Context {
+	if _, ok := conn.(*adminConn); ok {
+		return context.WithValue(ctx, adminContextKey{}, true)
+	}
+	return ctx
+}
+
+// isAdmin reports whether the call or request came through an admin listener.
+func isAdmin(ctx context.Context) bool {
+	if admin, _ := ctx.Value(adminContextKey{}).(bool); admin {
+		return true
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		_, admin := p.AuthInfo.(adminAuthInfo)
+		return admin
+	}
+	return false
+}
+
+func isAdminMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+admin.AdminService_ServiceDesc.ServiceName+"/")
+}
+
+func adminUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if isAdminMethod(info.FullMethod) && !isAdmin(ctx) {
+		return nil, errAdminDenied
+	}
+	return handler(ctx, req)
+}
+
+func adminStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isAdminMethod(info.FullMethod) && !isAdmin(ss.Context()) {
+		return errAdminDenied
+	}
+	return handler(srv, ss)
+}
+
+// adminOnly rejects HTTP requests that did not come through an admin listener.
+func adminOnly(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isAdmin(r.Context()) {
+			writeError(w, errAdminDenied)
+			return
+		}
+		handler(w, r)
+	}
+}
@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/MagicRodri/grpc_with_go/config"
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/admin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminAPIOnlyOnAdminListeners(t *testing.T) {
+	for _, httpEnabled := range []bool{false, true} {
+		name := "grpc"
+		if httpEnabled {
+			name = "http"
+		}
+		t.Run(name, func(t *testing.T) {
+			s := startServer(t, &config.GrpcConfig{
+				Listeners: testListeners(t),
+				HTTP:      config.HTTPConfig{Enabled: httpEnabled, AdminPath: "/admin"},
+			})
+
+			// without an initialized logger the admin API answers FailedPrecondition
+			tests := []struct {
+				listener int
+				code     codes.Code
+				http     int
+			}{
+				{listener: 0, code: codes.PermissionDenied, http: http.StatusForbidden},
+				{listener: 1, code: codes.FailedPrecondition, http: http.StatusBadRequest},
+			}
+			for _, tt := range tests {
+				target := target(s, tt.listener)
+
+				client := admin.NewAdminServiceClient(dial(t, target))
+				_, err := client.GetLogLevels(context.Background(), &admin.GetLogLevelsRequest{})
+				if code := status.Code(err); code != tt.code {
+					t.Errorf("GetLogLevels on %s: code %v, want %v", target, code, tt.code)
+				}
+
+				stream, err := client.StreamLogs(context.Background(), &admin.StreamLogsRequest{})
+				if err == nil {
+					_, err = stream.Recv()
+				}
+				if code := status.Code(err); code != tt.code {
+					t.Errorf("StreamLogs on %s: code %v, want %v", target, code, tt.code)
+				}
+
+				if !httpEnabled {
+					continue
+				}
+				res, err := httpClient(target).Get("http://server/admin/log-levels")
+				if err != nil {
+					t.Fatal(err)
+				}
+				res.Body.Close()
+				if res.StatusCode != tt.http {
+					t.Errorf("GET /admin/log-levels on %s: status %d, want %d", target, res.StatusCode, tt.http)
+				}
+			}
+		})
+	}
+}
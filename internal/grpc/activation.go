@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Environment of socket activation, as set by systemd or by Reexec.
+// LISTEN_PID is optional: Reexec cannot know the pid of the new process.
+const (
+	listenFDsEnv     = "LISTEN_FDS"
+	listenPIDEnv     = "LISTEN_PID"
+	listenFDNamesEnv = "LISTEN_FDNAMES"
+	// listenFDsStart is the first inherited descriptor, after stdin, stdout and stderr
+	listenFDsStart = 3
+	fdScheme       = "fd://"
+)
+
+// inheritedListener is a listening socket passed by the parent process.
+type inheritedListener struct {
+	net.Listener
+	fd      int
+	name    string
+	claimed bool
+}
+
+// claimInherited returns the inherited listener the endpoint refers to: by
+// descriptor number or name for fd://3 and fd://grpc, or by address for
+// the other endpoints, so that a restarted server keeps its sockets.
+func claimInherited(address string, inherited []*inheritedListener) (net.Listener, error) {
+	ref, isFD := strings.CutPrefix(address, fdScheme)
+	for _, l := range inherited {
+		if l.claimed {
+			continue
+		}
+		var match bool
+		if isFD {
+			match = ref == l.name || ref == strconv.Itoa(l.fd)
+		} else {
+			match = sameAddress(address, l.Addr())
+		}
+		if match {
+			l.claimed = true
+			return l.Listener, nil
+		}
+	}
+	if isFD {
+		return nil, fmt.Errorf("no inherited listener %s", address)
+	}
+	return nil, nil
+}
+
+// sameAddress reports whether a listener bound to addr serves the configured address.
+func sameAddress(address string, addr net.Addr) bool {
+	if path, ok := strings.CutPrefix(address, unixScheme); ok {
+		return addr.Network() == "unix" && addr.String() == path
+	}
+
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	want, err := net.ResolveTCPAddr("tcp", strings.TrimPrefix(address, "tcp://"))
+	if err != nil || want.Port != tcp.Port {
+		return false
+	}
+	if want.IP == nil || want.IP.IsUnspecified() {
+		return tcp.IP == nil || tcp.IP.IsUnspecified()
+	}
+	return want.IP.Equal(tcp.IP)
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package grpc
+
+// inheritedListeners: socket activation is only supported on unix.
+func inheritedListeners() ([]*inheritedListener, error) {
+	return nil, nil
+}
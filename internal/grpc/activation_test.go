@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSameAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		addr    net.Addr
+		want    bool
+	}{
+		{address: "127.0.0.1:50051", addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50051}, want: true},
+		{address: "tcp://127.0.0.1:50051", addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50051}, want: true},
+		{address: "127.0.0.1:50052", addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50051}, want: false},
+		{address: "10.0.0.1:50051", addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50051}, want: false},
+		{address: ":50051", addr: &net.TCPAddr{IP: net.IPv6unspecified, Port: 50051}, want: true},
+		{address: "0.0.0.0:50051", addr: &net.TCPAddr{Port: 50051}, want: true},
+		{address: ":50051", addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 50051}, want: false},
+		{address: "unix:///run/app.sock", addr: &net.UnixAddr{Name: "/run/app.sock", Net: "unix"}, want: true},
+		{address: "unix:///run/other.sock", addr: &net.UnixAddr{Name: "/run/app.sock", Net: "unix"}, want: false},
+		{address: "127.0.0.1:50051", addr: &net.UnixAddr{Name: "/run/app.sock", Net: "unix"}, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := sameAddress(tt.address, tt.addr); got != tt.want {
+			t.Errorf("sameAddress(%q, %v) = %v, want %v", tt.address, tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestClaimInherited(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcp.Close()
+	unix, err := net.Listen("unix", filepath.Join(t.TempDir(), "grpc.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Close()
+
+	newInherited := func() []*inheritedListener {
+		return []*inheritedListener{
+			{Listener: tcp, fd: 3, name: "public"},
+			{Listener: unix, fd: 4, name: "admin"},
+		}
+	}
+
+	tests := []struct {
+		address string
+		want    net.Listener
+		wantErr bool
+	}{
+		{address: "fd://3", want: tcp},
+		{address: "fd://admin", want: unix},
+		{address: "fd://5", wantErr: true},
+		{address: "fd://other", wantErr: true},
+		{address: tcp.Addr().String(), want: tcp},
+		{address: "unix://" + unix.Addr().String(), want: unix},
+		{address: "127.0.0.1:1", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			inherited := newInherited()
+			got, err := claimInherited(tt.address, inherited)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("claimed %v, want %v", got, tt.want)
+			}
+			if tt.want == nil {
+				return
+			}
+			// a listener is handed out only once
+			if again, err := claimInherited(tt.address, inherited); again != nil || (err != nil) != strings.HasPrefix(tt.address, fdScheme) {
+				t.Fatalf("second claim = %v, %v", again, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+//go:build unix
+
+package grpc
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// inheritedListeners takes over the sockets passed with LISTEN_FDS. The
+// variables are removed so that child processes do not take them too.
+func inheritedListeners() ([]*inheritedListener, error) {
+	defer func() {
+		os.Unsetenv(listenFDsEnv)
+		os.Unsetenv(listenPIDEnv)
+		os.Unsetenv(listenFDNamesEnv)
+	}()
+
+	count := os.Getenv(listenFDsEnv)
+	if count == "" {
+		return nil, nil
+	}
+	if pid := os.Getenv(listenPIDEnv); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		// meant for another process
+		return nil, nil
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid %s=%q", listenFDsEnv, count)
+	}
+	var names []string
+	if value := os.Getenv(listenFDNamesEnv); value != "" {
+		names = strings.Split(value, ":")
+	}
+
+	listeners := make([]*inheritedListener, 0, n)
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		syscall.CloseOnExec(fd)
+		name := ""
+		if i := fd - listenFDsStart; i < len(names) {
+			name = names[i]
+		}
+
+		file := os.NewFile(uintptr(fd), name)
+		l, err := net.FileListener(file)
+		// FileListener duplicates the descriptor
+		file.Close()
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("inherited descriptor %d is not a listening socket: %w", fd, err)
+		}
+		listeners = append(listeners, &inheritedListener{Listener: l, fd: fd, name: name})
+	}
+	return listeners, nil
+}
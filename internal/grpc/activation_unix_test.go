@@ -0,0 +1,99 @@
+//go:build unix
+
+package grpc
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const inheritedHelperEnv = "TEST_INHERITED_LISTENERS_HELPER"
+
+// TestInheritedListenersHelper runs in the child process started by
+// TestInheritedListeners, whose descriptors 3 and 4 are listening sockets.
+func TestInheritedListenersHelper(t *testing.T) {
+	if os.Getenv(inheritedHelperEnv) == "" {
+		t.Skip("helper process")
+	}
+
+	listeners, err := inheritedListeners()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range listeners {
+		fmt.Printf("listener %d %s %s\n", l.fd, l.name, l.Addr())
+	}
+	if os.Getenv(listenFDsEnv) != "" || os.Getenv(listenFDNamesEnv) != "" {
+		t.Fatal("activation variables were not removed")
+	}
+}
+
+func TestInheritedListeners(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcp.Close()
+	unix, err := net.Listen("unix", filepath.Join(t.TempDir(), "grpc.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Close()
+
+	var files []*os.File
+	for _, l := range []net.Listener{tcp, unix} {
+		f, err := l.(interface{ File() (*os.File, error) }).File()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		files = append(files, f)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestInheritedListenersHelper$", "-test.v")
+	cmd.ExtraFiles = files
+	cmd.Env = append(os.Environ(),
+		inheritedHelperEnv+"=1",
+		listenFDsEnv+"=2",
+		listenFDNamesEnv+"=public:admin",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("helper failed: %v\n%s", err, out)
+	}
+
+	for _, want := range []string{
+		fmt.Sprintf("listener 3 public %s", tcp.Addr()),
+		fmt.Sprintf("listener 4 admin %s", unix.Addr()),
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Fatalf("helper output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestInheritedListenersIgnoresOtherProcess(t *testing.T) {
+	t.Setenv(listenFDsEnv, "2")
+	t.Setenv(listenPIDEnv, "1")
+
+	listeners, err := inheritedListeners()
+	if err != nil || len(listeners) != 0 {
+		t.Fatalf("inheritedListeners = %v, %v, want none", listeners, err)
+	}
+	if _, ok := os.LookupEnv(listenFDsEnv); ok {
+		t.Fatal("LISTEN_FDS was not removed")
+	}
+}
+
+func TestInheritedListenersInvalidCount(t *testing.T) {
+	t.Setenv(listenFDsEnv, "two")
+
+	if _, err := inheritedListeners(); err == nil {
+		t.Fatal("inheritedListeners accepted LISTEN_FDS=two")
+	}
+}
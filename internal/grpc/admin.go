@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/admin"
+	"github.com/MagicRodri/grpc_with_go/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *Server) GetLogLevels(_ context.Context, _ *admin.GetLogLevelsRequest) (*admin.GetLogLevelsResponse, error) {
+	levels := logger.Levels()
+	if levels == nil {
+		return nil, status.Error(codes.FailedPrecondition, "logger is not initialized")
+	}
+
+	res := &admin.GetLogLevelsResponse{}
+	for component, level := range levels.Levels() {
+		res.Levels = append(res.Levels, &admin.LogLevel{Component: component, Level: logger.LevelName(level)})
+	}
+	slices.SortFunc(res.Levels, func(a, b *admin.LogLevel) int {
+		return strings.Compare(a.Component, b.Component)
+	})
+	return res, nil
+}
+
+func (s *Server) SetLogLevel(_ context.Context, in *admin.SetLogLevelRequest) (*admin.LogLevel, error) {
+	levels := logger.Levels()
+	if levels == nil {
+		return nil, status.Error(codes.FailedPrecondition, "logger is not initialized")
+	}
+	if in.GetComponent() == "" {
+		return nil, status.Error(codes.InvalidArgument, "component is required")
+	}
+
+	if in.GetLevel() == "" {
+		if in.GetComponent() == logger.RootComponent {
+			return nil, status.Error(codes.InvalidArgument, "root level cannot be reset")
+		}
+		levels.ResetLevel(in.GetComponent())
+	} else {
+		level, err := logger.ParseLevel(in.GetLevel())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		levels.SetLevel(in.GetComponent(), level)
+	}
+
+	current := logger.LevelName(levels.Levels()[in.GetComponent()])
+	s.log.Info("Log level changed", "target", in.GetComponent(), "level", current)
+	return &admin.LogLevel{Component: in.GetComponent(), Level: current}, nil
+}
+
+func (s *Server) StreamLogs(in *admin.StreamLogsRequest, stream grpc.ServerStreamingServer[admin.LogRecord]) error {
+	ring := logger.Ring()
+	if ring == nil {
+		return status.Error(codes.FailedPrecondition, "log ring buffer is disabled")
+	}
+
+	minLevel := slog.Level(math.MinInt)
+	if in.GetMinLevel() != "" {
+		level, err := logger.ParseLevel(in.GetMinLevel())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		minLevel = level
+	}
+	match := func(e *logger.Entry) bool {
+		if e.Level < minLevel {
+			return false
+		}
+		for key, value := range in.GetFields() {
+			if v, ok := e.Field(key); !ok || v != value {
+				return false
+			}
+		}
+		return true
+	}
+
+	// subscribe before taking the snapshot so that no record falls in between
+	var tail <-chan logger.Entry
+	if in.GetFollow() {
+		entries, cancel := ring.Subscribe()
+		defer cancel()
+		tail = entries
+	}
+
+	var backlog []logger.Entry
+	for _, e := range ring.Snapshot() {
+		if match(&e) {
+			backlog = append(backlog, e)
+		}
+	}
+	if limit := int(in.GetLimit()); limit > 0 && len(backlog) > limit {
+		backlog = backlog[len(backlog)-limit:]
+	}
+
+	var lastSeq uint64
+	for i := range backlog {
+		if err := stream.Send(logRecord(&backlog[i])); err != nil {
+			return err
+		}
+		lastSeq = backlog[i].Seq
+	}
+	if tail == nil {
+		return nil
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e, ok := <-tail:
+			if !ok {
+				return nil
+			}
+			if e.Seq <= lastSeq || !match(&e) {
+				continue
+			}
+			if err := stream.Send(logRecord(&e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func logRecord(e *logger.Entry) *admin.LogRecord {
+	record := &admin.LogRecord{
+		Seq:     e.Seq,
+		Time:    timestamppb.New(e.Time),
+		Level:   logger.LevelName(e.Level),
+		Message: e.Message,
+		Fields:  make([]*admin.LogField, len(e.Fields)),
+	}
+	for i, f := range e.Fields {
+		record.Fields[i] = &admin.LogField{Key: f.Key, Value: f.Value}
+	}
+	return record
+}
@@ -0,0 +1,150 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/MagicRodri/grpc_with_go/config"
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/admin"
+	"github.com/MagicRodri/grpc_with_go/pkg/logger"
+)
+
+const streamLogsHelperEnv = "TEST_STREAM_LOGS_HELPER"
+
+// TestStreamLogs runs the StreamLogs tests in a child process, since they
+// initialize the global logger that the other tests expect to be unset.
+func TestStreamLogs(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestStreamLogsHelper$", "-test.v")
+	cmd.Env = append(os.Environ(), streamLogsHelperEnv+"=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("helper failed: %v\n%s", err, out)
+	}
+}
+
+// recvMessages reads the records of stream until it ends or n arrived.
+func recvMessages(t *testing.T, stream admin.AdminService_StreamLogsClient, n int) []*admin.LogRecord {
+	t.Helper()
+
+	var records []*admin.LogRecord
+	for n < 0 || len(records) < n {
+		record, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func messages(records []*admin.LogRecord) []string {
+	var msgs []string
+	for _, r := range records {
+		msgs = append(msgs, r.GetMessage())
+	}
+	return msgs
+}
+
+func TestStreamLogsHelper(t *testing.T) {
+	if os.Getenv(streamLogsHelperEnv) == "" {
+		t.Skip("helper process")
+	}
+
+	cfg := &logger.Config{
+		SinkConfig: logger.SinkConfig{Output: "stderr"},
+		RingBuffer: logger.RingBufferConfig{Size: 1000},
+	}
+	if err := logger.InitDefault(cfg); err != nil {
+		t.Fatal(err)
+	}
+	log := logger.Component("test")
+	for i, level := range []string{"info", "warn", "info", "warn"} {
+		ctx := logger.AppendCtx(context.Background(), "request_id", "r"+strconv.Itoa(i%2))
+		if level == "warn" {
+			log.WarnContext(ctx, "record "+strconv.Itoa(i))
+		} else {
+			log.InfoContext(ctx, "record "+strconv.Itoa(i))
+		}
+	}
+
+	s := startServer(t, &config.GrpcConfig{Listeners: testListeners(t)})
+	client := admin.NewAdminServiceClient(dial(t, target(s, 1)))
+
+	tests := []struct {
+		name string
+		req  *admin.StreamLogsRequest
+		want []string
+	}{
+		{
+			name: "all",
+			req:  &admin.StreamLogsRequest{Fields: map[string]string{"component": "test"}},
+			want: []string{"record 0", "record 1", "record 2", "record 3"},
+		},
+		{
+			name: "level",
+			req:  &admin.StreamLogsRequest{MinLevel: "warn", Fields: map[string]string{"component": "test"}},
+			want: []string{"record 1", "record 3"},
+		},
+		{
+			name: "request_id",
+			req:  &admin.StreamLogsRequest{Fields: map[string]string{"request_id": "r0"}},
+			want: []string{"record 0", "record 2"},
+		},
+		{
+			name: "limit",
+			req:  &admin.StreamLogsRequest{Limit: 2, Fields: map[string]string{"component": "test"}},
+			want: []string{"record 2", "record 3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.StreamLogs(context.Background(), tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := messages(recvMessages(t, stream, -1)); !slices.Equal(got, tt.want) {
+				t.Fatalf("records = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("follow", func(t *testing.T) {
+		const total = 300
+		// records are logged while the stream switches from the backlog to the tail
+		started := make(chan struct{})
+		go func() {
+			for i := range total {
+				if i == total/3 {
+					close(started)
+				}
+				log.Info(strconv.Itoa(i), "follow", true)
+				time.Sleep(time.Millisecond)
+			}
+		}()
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream, err := client.StreamLogs(ctx, &admin.StreamLogsRequest{Follow: true, Fields: map[string]string{"follow": "true"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		records := recvMessages(t, stream, total)
+		for i, r := range records {
+			if r.GetMessage() != strconv.Itoa(i) {
+				t.Fatalf("record %d = %s, want every record once and in order: %v", i, r.GetMessage(), messages(records))
+			}
+			if i > 0 && r.GetSeq() <= records[i-1].GetSeq() {
+				t.Fatalf("seq %d after %d", r.GetSeq(), records[i-1].GetSeq())
+			}
+		}
+	})
+}
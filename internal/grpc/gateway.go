@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/helloworld"
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/openapi"
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/status"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// registerGateway serves the REST/JSON mapping of Greeter and StatusService
+// declared with google.api.http in the protos, e.g. GET /v1/status/{uuid}.
+// Requests call the server in process, without a gRPC round trip.
+func (s *Server) registerGateway() error {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+		runtime.WithErrorHandler(gatewayError),
+		runtime.WithRoutingErrorHandler(gatewayRoutingError),
+	)
+
+	ctx := context.Background()
+	if err := helloworld.RegisterGreeterHandlerServer(ctx, mux, s); err != nil {
+		return fmt.Errorf("failed to register Greeter gateway: %w", err)
+	}
+	if err := status.RegisterStatusServiceHandlerServer(ctx, mux, s); err != nil {
+		return fmt.Errorf("failed to register StatusService gateway: %w", err)
+	}
+
+	s.httpMux.Handle("/v1/", mux)
+	s.httpMux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openapi.Spec)
+	})
+	return nil
+}
+
+// gatewayError responds like the other HTTP handlers, keeping the HTTP status
+// of an error that carries one.
+func gatewayError(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+	var httpErr *runtime.HTTPStatusError
+	if errors.As(err, &httpErr) {
+		writeStatus(w, httpErr.HTTPStatus, grpcstatus.Convert(httpErr.Err))
+		return
+	}
+	writeError(w, err)
+}
+
+// gatewayRoutingError keeps the HTTP status of a request that matches no
+// route, e.g. 405 for a method the path does not support.
+func gatewayRoutingError(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, code int) {
+	c := codes.Internal
+	switch code {
+	case http.StatusBadRequest:
+		c = codes.InvalidArgument
+	case http.StatusNotFound:
+		c = codes.NotFound
+	case http.StatusMethodNotAllowed:
+		c = codes.Unimplemented
+	}
+	writeStatus(w, code, grpcstatus.New(c, http.StatusText(code)))
+}
@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MagicRodri/grpc_with_go/config"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+func newGatewayServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	s, err := NewServer(&config.GrpcConfig{HTTP: config.HTTPConfig{Enabled: true, Gateway: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// request sends a request to the gateway and decodes the JSON response.
+func request(t *testing.T, method, url, body string) (int, map[string]any) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var decoded map[string]any
+	if err := json.NewDecoder(res.Body).Decode(&decoded); err != nil {
+		t.Fatalf("%s %s: decoding response: %v", method, url, err)
+	}
+	return res.StatusCode, decoded
+}
+
+func TestGatewayGetStatus(t *testing.T) {
+	server := newGatewayServer(t)
+
+	code, body := request(t, http.MethodGet, server.URL+"/v1/status/abc", "")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, body %v", code, body)
+	}
+	if body["uuid"] != "abc" || body["message"] != "Status retrieved" {
+		t.Fatalf("body = %v, want uuid abc and the GetStatus message", body)
+	}
+	// unpopulated fields are emitted under their proto names
+	if _, ok := body["code"]; !ok {
+		t.Fatalf("body = %v, want the code field", body)
+	}
+}
+
+func TestGatewayErrors(t *testing.T) {
+	server := newGatewayServer(t)
+
+	tests := []struct {
+		method, path, body string
+		wantStatus         int
+		wantCode           codes.Code
+	}{
+		{method: http.MethodPost, path: "/v1/hello", body: "{", wantStatus: http.StatusBadRequest, wantCode: codes.InvalidArgument},
+		{method: http.MethodPatch, path: "/v1/status/abc", wantStatus: http.StatusMethodNotAllowed, wantCode: codes.Unimplemented},
+		{method: http.MethodGet, path: "/v1/unknown", wantStatus: http.StatusNotFound, wantCode: codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			code, body := request(t, tt.method, server.URL+tt.path, tt.body)
+			if code != tt.wantStatus || body["code"] != tt.wantCode.String() {
+				t.Fatalf("got %d %v, want %d with code %s", code, body, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestGatewayErrorMapping(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+	}{
+		{err: grpcstatus.Error(codes.NotFound, "no status"), wantStatus: http.StatusNotFound},
+		{err: grpcstatus.Error(codes.AlreadyExists, "exists"), wantStatus: http.StatusConflict},
+		{err: grpcstatus.Error(codes.Unavailable, "down"), wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		gatewayError(t.Context(), nil, nil, rec, nil, tt.err)
+
+		var body map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		st := grpcstatus.Convert(tt.err)
+		if rec.Code != tt.wantStatus || body["code"] != st.Code().String() || body["message"] != st.Message() {
+			t.Errorf("%v: got %d %v, want %d", tt.err, rec.Code, body, tt.wantStatus)
+		}
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	server := newGatewayServer(t)
+
+	code, body := request(t, http.MethodGet, server.URL+"/openapi.json", "")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d", code)
+	}
+	paths, _ := body["paths"].(map[string]any)
+	if _, ok := paths["/v1/status/{uuid}"]; !ok {
+		t.Fatalf("paths = %v, want /v1/status/{uuid}", paths)
+	}
+}
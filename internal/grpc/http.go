@@ -0,0 +1,195 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/admin"
+	"github.com/MagicRodri/grpc_with_go/pkg/logger"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// HandleHTTP registers an HTTP handler next to the built-in ones, e.g. for
+// metrics. It is only reachable when HTTP is enabled in GrpcConfig.
+func (s *Server) HandleHTTP(pattern string, handler http.Handler) {
+	s.httpMux.Handle(pattern, handler)
+}
+
+func (s *Server) registerHTTPHandlers() error {
+	if s.httpCfg.HealthPath != "" {
+		s.httpMux.HandleFunc("GET "+s.httpCfg.HealthPath, s.handleHealth)
+	}
+	if s.httpCfg.AdminPath != "" {
+		prefix := strings.TrimSuffix(s.httpCfg.AdminPath, "/")
+		s.httpMux.HandleFunc("GET "+prefix+"/log-levels", adminOnly(s.handleGetLogLevels))
+		s.httpMux.HandleFunc("PUT "+prefix+"/log-levels/{component}", adminOnly(s.handleSetLogLevel))
+		s.httpMux.HandleFunc("DELETE "+prefix+"/log-levels/{component}", adminOnly(s.handleSetLogLevel))
+	}
+	if s.httpCfg.Gateway {
+		return s.registerGateway()
+	}
+	return nil
+}
+
+// ServeHTTP sends gRPC requests to the gRPC server and the others to the HTTP handlers.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		s.grpcServer.ServeHTTP(w, r)
+		return
+	}
+	s.httpMux.ServeHTTP(w, r)
+}
+
+// newHTTPServer returns a server for gRPC and HTTP. Plain text HTTP/2 (h2c) is
+// accepted with prior knowledge, which is how gRPC clients connect without TLS.
+func (s *Server) newHTTPServer() *http.Server {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return &http.Server{
+		Handler:     s,
+		Protocols:   protocols,
+		ConnContext: adminConnContext,
+	}
+}
+
+func serveHTTP(srv *http.Server, l net.Listener) error {
+	if err := srv.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// stopHTTP lets in-flight requests, gRPC calls included, finish within the
+// shutdown timeout and then closes the remaining connections.
+func (s *Server) stopHTTP() {
+	s.mutex.Lock()
+	servers := s.httpServers
+	s.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			s.log.Warn("HTTP shutdown timed out, closing connections", logger.Err(err))
+			srv.Close()
+		}
+	}
+	// GracefulStop cannot drain calls served through ServeHTTP
+	s.grpcServer.Stop()
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	res, err := s.health.Check(r.Context(), &healthpb.HealthCheckRequest{Service: r.URL.Query().Get("service")})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	code := http.StatusOK
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		code = http.StatusServiceUnavailable
+	}
+	writeProto(w, code, res)
+}
+
+func (s *Server) handleGetLogLevels(w http.ResponseWriter, r *http.Request) {
+	res, err := s.GetLogLevels(r.Context(), &admin.GetLogLevelsRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, res)
+}
+
+// handleSetLogLevel changes the level of a component with PUT {"level": "debug"}
+// and resets it to the root level with DELETE.
+func (s *Server) handleSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	req := &admin.SetLogLevelRequest{}
+	if r.Method == http.MethodPut {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err == nil {
+			err = protojson.Unmarshal(body, req)
+		}
+		if err != nil {
+			writeError(w, status.Errorf(codes.InvalidArgument, "invalid request body: %v", err))
+			return
+		}
+		if req.GetLevel() == "" {
+			writeError(w, status.Error(codes.InvalidArgument, "level is required, use DELETE to reset it"))
+			return
+		}
+	}
+	req.Component = r.PathValue("component")
+
+	res, err := s.SetLogLevel(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeProto(w, http.StatusOK, res)
+}
+
+func writeProto(w http.ResponseWriter, code int, msg proto.Message) {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		writeError(w, status.Errorf(codes.Internal, "failed to encode response: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// writeError responds with the HTTP status matching the gRPC code of err.
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	writeStatus(w, httpStatus(st.Code()), st)
+}
+
+func writeStatus(w http.ResponseWriter, code int, st *status.Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]any{
+		"code":    st.Code().String(),
+		"message": st.Message(),
+	})
+}
+
+// httpStatus maps gRPC codes as described in google/rpc/code.proto.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,115 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/MagicRodri/grpc_with_go/config"
+)
+
+const unixScheme = "unix://"
+
+// listen opens every configured listener, reusing the inherited ones (see
+// claimInherited). Inherited listeners no endpoint refers to, e.g. sockets
+// of a systemd socket unit, are served as well. names holds the LISTEN_FDNAMES
+// name of every inherited listener. On failure every listener is closed.
+func listen(endpoints []config.ListenerConfig) (listeners []net.Listener, names []string, err error) {
+	inherited, err := inheritedListeners()
+	if err != nil {
+		return nil, nil, err
+	}
+	nameOf := func(l net.Listener) string {
+		for _, il := range inherited {
+			if il.Listener == l {
+				return il.name
+			}
+		}
+		return ""
+	}
+
+	listeners = make([]net.Listener, 0, len(endpoints)+len(inherited))
+	for _, endpoint := range endpoints {
+		l, err := claimInherited(endpoint.Address, inherited)
+		if err == nil && l == nil {
+			l, err = listenOne(endpoint)
+		}
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			for _, l := range inherited {
+				if !l.claimed {
+					l.Close()
+				}
+			}
+			return nil, nil, err
+		}
+		listeners = append(listeners, l)
+	}
+	for _, l := range inherited {
+		if !l.claimed {
+			listeners = append(listeners, l.Listener)
+		}
+	}
+
+	names = make([]string, len(listeners))
+	for i, l := range listeners {
+		names[i] = nameOf(l)
+	}
+	return listeners, names, nil
+}
+
+func listenOne(endpoint config.ListenerConfig) (net.Listener, error) {
+	path, ok := strings.CutPrefix(endpoint.Address, unixScheme)
+	if !ok {
+		address := strings.TrimPrefix(endpoint.Address, "tcp://")
+		l, err := net.Listen("tcp", address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+		}
+		return l, nil
+	}
+
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	l, err := listenUnix(path, endpoint.Mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", endpoint.Address, err)
+	}
+	return l, nil
+}
+
+// removeStaleSocket deletes the socket left by a server that did not shut down
+// cleanly. Other files are never removed.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", path, err)
+	}
+	if info.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("failed to listen on %s: file exists and is not a socket", path)
+	}
+	// a socket accepting connections belongs to a running server
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("failed to listen on %s: socket is in use", path)
+	}
+	return os.Remove(path)
+}
+
+// listenerAddress names a listener in logs, e.g. tcp://127.0.0.1:50051 or unix:///run/app.sock.
+func listenerAddress(l net.Listener) string {
+	addr := l.Addr()
+	if addr.Network() == "unix" {
+		return unixScheme + addr.String()
+	}
+	return addr.Network() + "://" + addr.String()
+}
@@ -0,0 +1,23 @@
+//go:build !unix
+
+package grpc
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+// listenUnix creates the socket at path and then sets its mode, as there is
+// no umask to apply it on creation.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	l, err := net.Listen("unix", path)
+	if err != nil || mode == 0 {
+		return l, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("failed to set mode %#o: %w", mode, err)
+	}
+	return l, nil
+}
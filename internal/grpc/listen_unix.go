@@ -0,0 +1,29 @@
+//go:build unix
+
+package grpc
+
+import (
+	"net"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// umaskMutex serializes the umask changes of concurrent listenUnix calls.
+var umaskMutex sync.Mutex
+
+// listenUnix creates the socket at path with mode from the start: the umask is
+// narrowed while the socket file is created, so that the socket is never
+// reachable with wider permissions. The umask is process-wide; files created
+// meanwhile by other goroutines only get fewer permissions.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if mode == 0 {
+		return net.Listen("unix", path)
+	}
+
+	umaskMutex.Lock()
+	defer umaskMutex.Unlock()
+	old := syscall.Umask(int(^mode & os.ModePerm))
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,39 @@
+//go:build unix
+
+package grpc
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/MagicRodri/grpc_with_go/config"
+)
+
+func TestListenUnixSocketMode(t *testing.T) {
+	// a permissive umask would leave the socket world-writable until a chmod
+	old := syscall.Umask(0)
+	defer syscall.Umask(old)
+
+	for _, mode := range []os.FileMode{0o600, 0o660} {
+		path := filepath.Join(t.TempDir(), "grpc.sock")
+		l, err := listenOne(config.ListenerConfig{Address: unixScheme + path, Mode: mode})
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(path)
+		l.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != mode {
+			t.Errorf("mode = %#o, want %#o", got, mode)
+		}
+	}
+
+	// the umask of the process is restored
+	if umask := syscall.Umask(0); umask != 0 {
+		t.Fatalf("umask = %#o after listening, want 0", umask)
+	}
+}
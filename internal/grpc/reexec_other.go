@@ -0,0 +1,18 @@
+//go:build !unix
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Reexec is only supported on unix.
+func (s *Server) Reexec(_ context.Context) error {
+	return fmt.Errorf("re-exec: %w", errors.ErrUnsupported)
+}
+
+func notifyReady() error {
+	return nil
+}
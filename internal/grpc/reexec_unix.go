@@ -0,0 +1,134 @@
+//go:build unix
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// readyFDEnv names the pipe a re-executed server writes to once it serves.
+const readyFDEnv = "REEXEC_READY_FD"
+
+// Reexec starts a new instance of the executable with the same arguments and
+// hands it the listening sockets through LISTEN_FDS, keeping their
+// LISTEN_FDNAMES names. It returns once the new
+// process serves on them; the caller then stops this server with Stop, which
+// finishes the in-flight calls while the new process accepts new ones.
+// If the new process fails to start in time, it is killed and this server keeps serving.
+func (s *Server) Reexec(ctx context.Context) error {
+	s.mutex.Lock()
+	listeners, names := s.listeners, s.listenerNames
+	s.mutex.Unlock()
+	if len(listeners) == 0 {
+		return errors.New("re-exec: server is not listening")
+	}
+
+	files := make([]*os.File, 0, len(listeners)+1)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	for _, l := range listeners {
+		filer, ok := l.(interface{ File() (*os.File, error) })
+		if !ok {
+			return fmt.Errorf("re-exec: cannot pass listener %s", listenerAddress(l))
+		}
+		f, err := filer.File()
+		if err != nil {
+			return fmt.Errorf("re-exec: failed to duplicate listener %s: %w", listenerAddress(l), err)
+		}
+		files = append(files, f)
+	}
+
+	ready, readyWriter, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("re-exec: %w", err)
+	}
+	defer ready.Close()
+	files = append(files, readyWriter)
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("re-exec: %w", err)
+	}
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(activationFreeEnv(),
+		listenFDsEnv+"="+strconv.Itoa(len(listeners)),
+		listenFDNamesEnv+"="+strings.Join(names, ":"),
+		readyFDEnv+"="+strconv.Itoa(listenFDsStart+len(listeners)),
+	)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("re-exec: failed to start %s: %w", executable, err)
+	}
+	// only the new process keeps the pipe open: EOF means it exited
+	readyWriter.Close()
+	files = files[:len(files)-1]
+
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := ready.Read(make([]byte, 1))
+		readErr <- err
+	}()
+	select {
+	case err := <-readErr:
+		if err != nil {
+			cmd.Wait()
+			return fmt.Errorf("re-exec: new process exited before serving: %s", cmd.ProcessState)
+		}
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		cmd.Wait()
+		return fmt.Errorf("re-exec: new process is not ready: %w", ctx.Err())
+	}
+
+	// the socket files now belong to the new process as well
+	for _, l := range listeners {
+		if ul, ok := l.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+	}
+	s.log.Info("listeners handed over to the new process", "pid", cmd.Process.Pid)
+	return nil
+}
+
+// notifyReady tells the parent of a re-executed server that it serves.
+func notifyReady() error {
+	value, ok := os.LookupEnv(readyFDEnv)
+	if !ok {
+		return nil
+	}
+	os.Unsetenv(readyFDEnv)
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s=%q", readyFDEnv, value)
+	}
+	pipe := os.NewFile(uintptr(fd), "ready")
+	defer pipe.Close()
+	_, err = pipe.Write([]byte{1})
+	return err
+}
+
+func activationFreeEnv() []string {
+	env := os.Environ()
+	result := env[:0:0]
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		switch name {
+		case listenFDsEnv, listenPIDEnv, listenFDNamesEnv, readyFDEnv:
+			continue
+		}
+		result = append(result, kv)
+	}
+	return result
+}
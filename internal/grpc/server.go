@@ -2,59 +2,213 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/MagicRodri/grpc_with_go/config"
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/admin"
 	"github.com/MagicRodri/grpc_with_go/pkg/generated/helloworld"
 	"github.com/MagicRodri/grpc_with_go/pkg/generated/status"
+	"github.com/MagicRodri/grpc_with_go/pkg/logger"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
+const defaultShutdownTimeout = 30 * time.Second
+
 // Server represents a gRPC server.
 type Server struct {
 	helloworld.GreeterServer
 	status.StatusServiceServer
+	admin.AdminServiceServer
 	grpcServer *grpc.Server
-	address    string
+	health     *health.Server
+	endpoints  []config.ListenerConfig
+	httpCfg    config.HTTPConfig
+	httpMux    *http.ServeMux
+	// shutdownTimeout bounds Stop in both modes
+	shutdownTimeout time.Duration
+	log             logger.LoggerInterface
+
+	mutex         sync.Mutex
+	listeners     []net.Listener
+	listenerNames []string
+	httpServers   []*http.Server
+	// stopped is set by Stop, so that a Start still opening listeners does not serve
+	stopped bool
 }
 
 // NewServer creates a new gRPC server instance.
-func NewServer(cfg *config.GrpcConfig) *Server {
-	return &Server{
-		grpcServer: grpc.NewServer(),
-		address:    cfg.Address,
+func NewServer(cfg *config.GrpcConfig) (*Server, error) {
+	s := &Server{
+		grpcServer: grpc.NewServer(
+			grpc.Creds(newAdminCredentials()),
+			grpc.ChainUnaryInterceptor(adminUnaryInterceptor),
+			grpc.ChainStreamInterceptor(adminStreamInterceptor),
+		),
+		health:    health.NewServer(),
+		endpoints: cfg.Endpoints(),
+		httpCfg:   cfg.HTTP,
+		httpMux:   http.NewServeMux(),
+		log:       logger.Component("grpc.server"),
+
+		shutdownTimeout: cfg.ShutdownTimeout,
+	}
+	if s.shutdownTimeout <= 0 {
+		s.shutdownTimeout = defaultShutdownTimeout
 	}
+	if err := s.registerHTTPHandlers(); err != nil {
+		return nil, err
+	}
+	return s, nil
 }
 
-// Start starts the gRPC server.
+// Start starts the gRPC server on every configured or inherited listener and
+// blocks until it is stopped. If serving on one listener fails, the server is stopped.
 func (s *Server) Start() error {
-	listener, err := net.Listen("tcp", s.address)
+	listeners, names, err := listen(s.endpoints)
 	if err != nil {
-		return fmt.Errorf("failed to listen on %s: %w", s.address, err)
+		return err
 	}
 	helloworld.RegisterGreeterServer(s.grpcServer, s)
 	status.RegisterStatusServiceServer(s.grpcServer, s)
+	admin.RegisterAdminServiceServer(s.grpcServer, s)
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
 	reflection.Register(s.grpcServer)
-	fmt.Printf("gRPC server listening on %s\n", s.address)
-	return s.grpcServer.Serve(listener)
+	for name := range s.grpcServer.GetServiceInfo() {
+		s.health.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	// every server is registered before any serves, so that Stop and halt reach all of them
+	serves := make([]func() error, len(listeners))
+	var httpServers []*http.Server
+	for i, listener := range listeners {
+		// inherited listeners no endpoint refers to come last and never serve the admin API
+		adminAPI := i < len(s.endpoints) && s.endpoints[i].Admin
+		if adminAPI {
+			listener = adminListener{Listener: listener}
+		}
+		s.log.Info("gRPC server listening", "address", listenerAddress(listener), "http", s.httpCfg.Enabled, "admin", adminAPI)
+		if s.httpCfg.Enabled {
+			srv := s.newHTTPServer()
+			httpServers = append(httpServers, srv)
+			serves[i] = func() error { return serveHTTP(srv, listener) }
+		} else {
+			serves[i] = func() error { return serveGRPC(s.grpcServer, listener) }
+		}
+	}
+
+	s.mutex.Lock()
+	if s.stopped {
+		s.mutex.Unlock()
+		for _, l := range listeners {
+			l.Close()
+		}
+		return nil
+	}
+	s.listeners = listeners
+	s.listenerNames = names
+	s.httpServers = httpServers
+	s.mutex.Unlock()
+
+	errCh := make(chan error, len(serves))
+	for _, serve := range serves {
+		go func() {
+			errCh <- serve()
+		}()
+	}
+	if err := notifyReady(); err != nil {
+		s.log.Warn("failed to notify the parent process", logger.Err(err))
+	}
+
+	var errs []error
+	for range listeners {
+		if err := <-errCh; err != nil {
+			errs = append(errs, err)
+			s.halt()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// serveGRPC serves l like serveHTTP: a stop before Serve is not an error.
+func serveGRPC(srv *grpc.Server, l net.Listener) error {
+	if err := srv.Serve(l); !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
 }
 
+// Stop lets in-flight calls finish within the shutdown timeout and then closes
+// the remaining connections.
 func (s *Server) Stop() {
-	s.grpcServer.GracefulStop()
-	fmt.Println("gRPC server stopped")
+	s.mutex.Lock()
+	s.stopped = true
+	s.mutex.Unlock()
+
+	// health checks report NOT_SERVING while in-flight calls finish
+	s.health.Shutdown()
+	if s.httpCfg.Enabled {
+		s.stopHTTP()
+	} else {
+		s.stopGRPC()
+	}
+	s.log.Info("gRPC server stopped")
 }
 
-// Serve starts the gRPC server and blocks until it is stopped.
-func (s *Server) Serve(ctx context.Context) error {
-	err := s.Start()
-	if err != nil {
-		return fmt.Errorf("failed to start gRPC server: %w", err)
+// stopGRPC bounds GracefulStop, which waits for every call including
+// streams that never end.
+func (s *Server) stopGRPC() {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	timer := time.NewTimer(s.shutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		s.log.Warn("gRPC graceful stop timed out, closing connections")
+		s.grpcServer.Stop()
+		<-done
 	}
+}
 
-	// Wait for the context to be done before stopping the server
-	<-ctx.Done()
-	s.Stop()
-	return nil
+// halt closes every listener and connection at once.
+func (s *Server) halt() {
+	s.mutex.Lock()
+	servers := s.httpServers
+	s.mutex.Unlock()
+	for _, srv := range servers {
+		srv.Close()
+	}
+	s.grpcServer.Stop()
+}
+
+// Serve starts the gRPC server and blocks until ctx is done or the server fails.
+func (s *Server) Serve(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Start()
+	}()
+
+	select {
+	case err := <-errCh:
+		// Start returns nil once the server is stopped by Stop
+		if err != nil {
+			return fmt.Errorf("failed to start gRPC server: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		s.Stop()
+		return <-errCh
+	}
 }
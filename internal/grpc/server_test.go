@@ -0,0 +1,225 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MagicRodri/grpc_with_go/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// startServer serves cfg in the background and returns the server once every
+// listener is open. The server is stopped when the test ends.
+func startServer(t *testing.T, cfg *config.GrpcConfig) *Server {
+	t.Helper()
+
+	s, err := NewServer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Start()
+	}()
+	t.Cleanup(func() {
+		s.Stop()
+		if err := <-errCh; err != nil {
+			t.Errorf("Start: %v", err)
+		}
+	})
+	waitListening(t, s, errCh)
+	return s
+}
+
+// waitListening waits until s has opened its listeners; errCh receives the
+// result of serving s.
+func waitListening(t *testing.T, s *Server, errCh chan error) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mutex.Lock()
+		ready := len(s.listeners) > 0
+		s.mutex.Unlock()
+		if ready {
+			return
+		}
+		select {
+		case err := <-errCh:
+			t.Fatalf("Start: %v", err)
+		default:
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("server did not start listening")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// target returns the dial target of the i-th listener of s: host:port or unix:///path.
+func target(s *Server, i int) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	addr := s.listeners[i].Addr()
+	if addr.Network() == "unix" {
+		return unixScheme + addr.String()
+	}
+	return addr.String()
+}
+
+func dial(t *testing.T, target string) *grpc.ClientConn {
+	t.Helper()
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// httpClient sends plain HTTP/1.1 requests to the listener at target.
+func httpClient(target string) *http.Client {
+	network, address := "tcp", target
+	if path, ok := strings.CutPrefix(target, unixScheme); ok {
+		network, address = "unix", path
+	}
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, address)
+			},
+		},
+	}
+}
+
+func testListeners(t *testing.T) []config.ListenerConfig {
+	return []config.ListenerConfig{
+		{Address: "127.0.0.1:0"},
+		{Address: unixScheme + filepath.Join(t.TempDir(), "admin.sock"), Admin: true},
+	}
+}
+
+func TestStopCutsOffStreams(t *testing.T) {
+	for _, httpEnabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("http=%v", httpEnabled), func(t *testing.T) {
+			s := startServer(t, &config.GrpcConfig{
+				Listeners:       []config.ListenerConfig{{Address: "127.0.0.1:0"}},
+				HTTP:            config.HTTPConfig{Enabled: httpEnabled},
+				ShutdownTimeout: 100 * time.Millisecond,
+			})
+
+			// a health watch never ends on its own
+			stream, err := healthpb.NewHealthClient(dial(t, target(s, 0))).Watch(context.Background(), &healthpb.HealthCheckRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := stream.Recv(); err != nil {
+				t.Fatal(err)
+			}
+
+			stopped := make(chan struct{})
+			go func() {
+				s.Stop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Stop did not return after the shutdown timeout")
+			}
+		})
+	}
+}
+
+func TestStopWhileStarting(t *testing.T) {
+	for _, httpEnabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("http=%v", httpEnabled), func(t *testing.T) {
+			s, err := NewServer(&config.GrpcConfig{
+				Listeners: testListeners(t),
+				HTTP:      config.HTTPConfig{Enabled: httpEnabled},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- s.Start()
+			}()
+			s.Stop()
+
+			select {
+			case err := <-errCh:
+				if err != nil {
+					t.Fatalf("Start: %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Start kept serving after Stop")
+			}
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(s *Server, cancel context.CancelFunc)
+	}{
+		{name: "context cancelled", stop: func(_ *Server, cancel context.CancelFunc) { cancel() }},
+		{name: "stopped", stop: func(s *Server, _ context.CancelFunc) { s.Stop() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewServer(&config.GrpcConfig{Listeners: []config.ListenerConfig{{Address: "127.0.0.1:0"}}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- s.Serve(ctx)
+			}()
+			waitListening(t, s, errCh)
+
+			tt.stop(s, cancel)
+			select {
+			case err := <-errCh:
+				if err != nil {
+					t.Fatalf("Serve = %v, want nil", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Serve did not return")
+			}
+		})
+	}
+}
+
+func TestServeListenError(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer taken.Close()
+
+	s, err := NewServer(&config.GrpcConfig{Listeners: []config.ListenerConfig{{Address: taken.Addr().String()}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Serve(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to start gRPC server") {
+		t.Fatalf("Serve = %v, want the listen error", err)
+	}
+}
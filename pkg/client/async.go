@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+const (
+	defaultAsyncWorkers   = 4
+	defaultAsyncQueueSize = 100
+)
+
+// Overflow policies of the asynchronous sender.
+const (
+	OverflowBlock      = "block"
+	OverflowDropOldest = "drop_oldest"
+	OverflowDropNewest = "drop_newest"
+)
+
+var (
+	// ErrSendDropped completes the future of a status discarded by the overflow policy.
+	ErrSendDropped = errors.New("status dropped: async queue is full")
+	// ErrSenderClosed completes the future of a status submitted after Close.
+	ErrSenderClosed = errors.New("async sender is closed")
+	// ErrSendPending is returned by SendFuture.Err while the send has not completed.
+	ErrSendPending = errors.New("status send is pending")
+)
+
+// SendFuture is the pending result of an asynchronous send.
+type SendFuture struct {
+	done chan struct{}
+	err  error
+}
+
+func newSendFuture() *SendFuture {
+	return &SendFuture{done: make(chan struct{})}
+}
+
+func (f *SendFuture) complete(err error) {
+	f.err = err
+	close(f.done)
+}
+
+// Done is closed once the send has completed.
+func (f *SendFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Err returns the result of the send, or ErrSendPending until Done is closed.
+func (f *SendFuture) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return ErrSendPending
+	}
+}
+
+// Wait blocks until the send completes or ctx is done.
+func (f *SendFuture) Wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type asyncJob struct {
+	status *Status
+	future *SendFuture
+}
+
+// asyncSender delivers statuses from a bounded queue with a fixed pool of workers.
+type asyncSender struct {
+	send     func(*Status) error
+	policy   string
+	capacity int
+
+	mutex    sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	queue    []*asyncJob
+	pending  int
+	idle     chan struct{}
+	closed   bool
+	workers  sync.WaitGroup
+}
+
+func newAsyncSender(cfg *AsyncConfig, send func(*Status) error) *asyncSender {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = defaultAsyncWorkers
+	}
+	capacity := cfg.QueueSize
+	if capacity <= 0 {
+		capacity = defaultAsyncQueueSize
+	}
+	policy := cfg.Overflow
+	if policy == "" {
+		policy = OverflowBlock
+	}
+
+	idle := make(chan struct{})
+	close(idle)
+
+	s := &asyncSender{
+		send:     send,
+		policy:   policy,
+		capacity: capacity,
+		idle:     idle,
+	}
+	s.notEmpty = sync.NewCond(&s.mutex)
+	s.notFull = sync.NewCond(&s.mutex)
+
+	s.workers.Add(workers)
+	for range workers {
+		go s.work()
+	}
+	return s
+}
+
+func (s *asyncSender) submit(status *Status) *SendFuture {
+	job := &asyncJob{status: status, future: newSendFuture()}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for !s.closed && len(s.queue) >= s.capacity {
+		switch s.policy {
+		case OverflowDropNewest:
+			job.future.complete(ErrSendDropped)
+			return job.future
+		case OverflowDropOldest:
+			oldest := s.queue[0]
+			s.queue = s.queue[1:]
+			s.finish()
+			oldest.future.complete(ErrSendDropped)
+		default:
+			s.notFull.Wait()
+		}
+	}
+	if s.closed {
+		job.future.complete(ErrSenderClosed)
+		return job.future
+	}
+
+	if s.pending == 0 {
+		s.idle = make(chan struct{})
+	}
+	s.pending++
+	s.queue = append(s.queue, job)
+	s.notEmpty.Signal()
+	return job.future
+}
+
+func (s *asyncSender) work() {
+	defer s.workers.Done()
+
+	for {
+		s.mutex.Lock()
+		for !s.closed && len(s.queue) == 0 {
+			s.notEmpty.Wait()
+		}
+		if len(s.queue) == 0 {
+			s.mutex.Unlock()
+			return
+		}
+		job := s.queue[0]
+		s.queue = s.queue[1:]
+		s.notFull.Signal()
+		s.mutex.Unlock()
+
+		job.future.complete(s.send(job.status))
+
+		s.mutex.Lock()
+		s.finish()
+		s.mutex.Unlock()
+	}
+}
+
+// finish marks one pending job as done. The caller must hold the mutex.
+func (s *asyncSender) finish() {
+	s.pending--
+	if s.pending == 0 {
+		close(s.idle)
+	}
+}
+
+// flush waits until every submitted status has been sent or dropped.
+func (s *asyncSender) flush(ctx context.Context) error {
+	s.mutex.Lock()
+	idle := s.idle
+	s.mutex.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close stops accepting statuses and waits until the queued ones are sent.
+func (s *asyncSender) close() {
+	s.mutex.Lock()
+	s.closed = true
+	s.notEmpty.Broadcast()
+	s.notFull.Broadcast()
+	s.mutex.Unlock()
+
+	s.workers.Wait()
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSendFutureErr(t *testing.T) {
+	future := newSendFuture()
+	if err := future.Err(); !errors.Is(err, ErrSendPending) {
+		t.Fatalf("Err before completion = %v, want ErrSendPending", err)
+	}
+
+	future.complete(nil)
+	if err := future.Err(); err != nil {
+		t.Fatalf("Err after success = %v, want nil", err)
+	}
+}
+
+func TestAsyncSenderOverflow(t *testing.T) {
+	tests := []struct {
+		policy string
+		// results of the first, the queued and the overflowing status
+		want [3]error
+	}{
+		{policy: OverflowDropNewest, want: [3]error{nil, nil, ErrSendDropped}},
+		{policy: OverflowDropOldest, want: [3]error{nil, ErrSendDropped, nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			release := make(chan struct{})
+			sending := make(chan struct{}, 3)
+			s := newAsyncSender(&AsyncConfig{Workers: 1, QueueSize: 1, Overflow: tt.policy}, func(*Status) error {
+				sending <- struct{}{}
+				<-release
+				return nil
+			})
+			defer s.close()
+
+			first := s.submit(&Status{Uuid: "first"})
+			// the only worker is busy with the first status
+			<-sending
+			queued := s.submit(&Status{Uuid: "queued"})
+			overflow := s.submit(&Status{Uuid: "overflow"})
+			close(release)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := s.flush(ctx); err != nil {
+				t.Fatalf("flush: %v", err)
+			}
+			for i, future := range []*SendFuture{first, queued, overflow} {
+				if err := future.Err(); err != tt.want[i] {
+					t.Errorf("future %d = %v, want %v", i, err, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAsyncSenderBlockPolicy(t *testing.T) {
+	release := make(chan struct{})
+	sending := make(chan struct{}, 3)
+	s := newAsyncSender(&AsyncConfig{Workers: 1, QueueSize: 1, Overflow: OverflowBlock}, func(*Status) error {
+		sending <- struct{}{}
+		<-release
+		return nil
+	})
+
+	first := s.submit(&Status{Uuid: "first"})
+	<-sending
+	queued := s.submit(&Status{Uuid: "queued"})
+
+	submitted := make(chan *SendFuture)
+	go func() { submitted <- s.submit(&Status{Uuid: "blocked"}) }()
+	select {
+	case <-submitted:
+		t.Fatal("submit did not block on a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		s.close()
+		close(closed)
+	}()
+	// close wakes the blocked submitter
+	var blocked *SendFuture
+	select {
+	case blocked = <-submitted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("submit still blocked after close")
+	}
+	if err := blocked.Err(); !errors.Is(err, ErrSenderClosed) {
+		t.Fatalf("blocked future = %v, want ErrSenderClosed", err)
+	}
+
+	// close sends the queued status before it returns
+	close(release)
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close did not return")
+	}
+	for i, future := range []*SendFuture{first, queued} {
+		if err := future.Err(); err != nil {
+			t.Errorf("future %d = %v, want nil", i, err)
+		}
+	}
+	if err := s.submit(&Status{Uuid: "late"}).Err(); !errors.Is(err, ErrSenderClosed) {
+		t.Fatalf("submit after close = %v, want ErrSenderClosed", err)
+	}
+}
@@ -3,9 +3,27 @@ package client
 import "github.com/MagicRodri/grpc_with_go/pkg/validation"
 
 type StatusServiceConfig struct {
-	Host    string `mapstructure:"host" validate:"required"`
-	Name    string `mapstructure:"name" validate:"required"`
-	Timeout int    `mapstructure:"timeout" validate:"required"`
+	Host    string       `mapstructure:"host" validate:"required,dial_target" desc:"Address of the status service: host:port, dns:///host:port, unix:///path/to.sock or another gRPC target"`
+	Name    string       `mapstructure:"name" validate:"required" default:"status" desc:"Client name used in logs and events"`
+	Timeout int          `mapstructure:"timeout" validate:"required" default:"5" desc:"Timeout of one request in seconds"`
+	Outbox  OutboxConfig `mapstructure:"outbox" desc:"Durable queue of statuses that could not be sent"`
+	Async   AsyncConfig  `mapstructure:"async" desc:"Settings of SendStatusAsync"`
+}
+
+// OutboxConfig enables persisting statuses that could not be sent.
+// MaxBytes caps the disk usage of the queue, 10 MiB by default.
+type OutboxConfig struct {
+	Enabled  bool   `mapstructure:"enabled" desc:"Queue failed statuses on disk and replay them"`
+	Dir      string `mapstructure:"dir" validate:"required_if=Enabled true" desc:"Directory of the queue"`
+	MaxBytes int64  `mapstructure:"max_bytes" validate:"gte=0" default:"10485760" desc:"Disk usage cap of the queue in bytes"`
+}
+
+// AsyncConfig tunes SendStatusAsync. Zero values select 4 workers, a queue of
+// 100 statuses and the block overflow policy.
+type AsyncConfig struct {
+	Workers   int    `mapstructure:"workers" validate:"gte=0" default:"4" desc:"Concurrent senders"`
+	QueueSize int    `mapstructure:"queue_size" validate:"gte=0" default:"100" desc:"Statuses waiting to be sent"`
+	Overflow  string `mapstructure:"overflow" validate:"omitempty,oneof=block drop_oldest drop_newest" default:"block" desc:"Policy when the queue is full"`
 }
 
 func (cfg *StatusServiceConfig) Validate() error {
@@ -0,0 +1,230 @@
+package client
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultOutboxMaxBytes int64 = 10 << 20
+	outboxFileExt               = ".json"
+)
+
+// ErrStatusQueued is returned when a status could not be delivered right away
+// and was persisted in the outbox to be replayed once the connection is ready.
+var ErrStatusQueued = errors.New("status queued for later delivery")
+
+// OutboxStats describes the current state of the status outbox. Dropped counts
+// records evicted by the size cap or unreadable on disk, Rejected the records
+// the server refused with a permanent error.
+type OutboxStats struct {
+	Depth    int
+	Bytes    int64
+	Dropped  uint64
+	Rejected uint64
+}
+
+type outboxRecord struct {
+	seq  uint64
+	size int64
+}
+
+// outbox is a durable FIFO of unsent statuses. Every record is stored in its own
+// file named after its sequence number, so the order survives restarts.
+// When the size cap is reached the oldest records are dropped.
+type outbox struct {
+	dir      string
+	maxBytes int64
+
+	mutex    sync.Mutex
+	records  []outboxRecord
+	bytes    int64
+	nextSeq  uint64
+	dropped  uint64
+	rejected uint64
+}
+
+func openOutbox(cfg *OutboxConfig) (*outbox, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory %s: %w", cfg.Dir, err)
+	}
+
+	entries, err := os.ReadDir(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox directory %s: %w", cfg.Dir, err)
+	}
+
+	o := &outbox{dir: cfg.Dir, maxBytes: cfg.MaxBytes}
+	if o.maxBytes <= 0 {
+		o.maxBytes = defaultOutboxMaxBytes
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, outboxFileExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, outboxFileExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat outbox record %s: %w", name, err)
+		}
+		o.records = append(o.records, outboxRecord{seq: seq, size: info.Size()})
+		o.bytes += info.Size()
+	}
+
+	slices.SortFunc(o.records, func(a, b outboxRecord) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+	if n := len(o.records); n > 0 {
+		o.nextSeq = o.records[n-1].seq + 1
+	}
+	return o, nil
+}
+
+func (o *outbox) path(seq uint64) string {
+	return filepath.Join(o.dir, fmt.Sprintf("%020d%s", seq, outboxFileExt))
+}
+
+// push persists the status at the tail of the queue.
+func (o *outbox) push(status *Status) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to encode status: %w", err)
+	}
+	size := int64(len(data))
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if size > o.maxBytes {
+		o.dropped++
+		return fmt.Errorf("status of %d bytes exceeds outbox capacity of %d bytes", size, o.maxBytes)
+	}
+	for len(o.records) > 0 && o.bytes+size > o.maxBytes {
+		o.dropped++
+		if err := o.removeHead(); err != nil {
+			return err
+		}
+	}
+
+	seq := o.nextSeq
+	if err := writeFileSync(o.path(seq), data); err != nil {
+		return err
+	}
+	o.nextSeq++
+	o.records = append(o.records, outboxRecord{seq: seq, size: size})
+	o.bytes += size
+	return nil
+}
+
+// peek returns the oldest status without removing it, or nil when the queue is
+// empty. Unreadable records are dropped.
+func (o *outbox) peek() (*Status, uint64, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for len(o.records) > 0 {
+		seq := o.records[0].seq
+		data, err := os.ReadFile(o.path(seq))
+		if err == nil {
+			var status Status
+			if err = json.Unmarshal(data, &status); err == nil {
+				return &status, seq, nil
+			}
+		}
+		o.dropped++
+		if err := o.removeHead(); err != nil {
+			return nil, 0, err
+		}
+	}
+	return nil, 0, nil
+}
+
+// pop removes the record seq if it is still at the head of the queue.
+func (o *outbox) pop(seq uint64) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if len(o.records) > 0 && o.records[0].seq == seq {
+		return o.removeHead()
+	}
+	return nil
+}
+
+// reject removes the record seq like pop and counts it as refused by the server.
+func (o *outbox) reject(seq uint64) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if len(o.records) > 0 && o.records[0].seq == seq {
+		o.rejected++
+		return o.removeHead()
+	}
+	return nil
+}
+
+// removeHead forgets the oldest record even if its file cannot be removed, so a
+// delivered status is not sent again before a restart; the error is returned.
+func (o *outbox) removeHead() error {
+	head := o.records[0]
+	o.records = o.records[1:]
+	o.bytes -= head.size
+	if err := os.Remove(o.path(head.seq)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove outbox record: %w", err)
+	}
+	return nil
+}
+
+func (o *outbox) depth() int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return len(o.records)
+}
+
+func (o *outbox) stats() OutboxStats {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return OutboxStats{Depth: len(o.records), Bytes: o.bytes, Dropped: o.dropped, Rejected: o.rejected}
+}
+
+// writeFileSync writes data to a temporary file, syncs it and renames it into place,
+// so a crash never leaves a partially written record behind.
+func writeFileSync(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox record: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write outbox record: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to sync outbox record: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to close outbox record: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to commit outbox record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"os"
+	"testing"
+)
+
+func openTestOutbox(t *testing.T, dir string, maxBytes int64) *outbox {
+	t.Helper()
+
+	o, err := openOutbox(&OutboxConfig{Enabled: true, Dir: dir, MaxBytes: maxBytes})
+	if err != nil {
+		t.Fatalf("openOutbox: %v", err)
+	}
+	return o
+}
+
+func pushAll(t *testing.T, o *outbox, uuids ...string) {
+	t.Helper()
+
+	for _, uuid := range uuids {
+		if err := o.push(&Status{Uuid: uuid}); err != nil {
+			t.Fatalf("push %s: %v", uuid, err)
+		}
+	}
+}
+
+// drain pops every record and returns the uuids in queue order.
+func drain(t *testing.T, o *outbox) []string {
+	t.Helper()
+
+	var uuids []string
+	for {
+		status, seq, err := o.peek()
+		if err != nil {
+			t.Fatalf("peek: %v", err)
+		}
+		if status == nil {
+			return uuids
+		}
+		uuids = append(uuids, status.Uuid)
+		if err := o.pop(seq); err != nil {
+			t.Fatalf("pop: %v", err)
+		}
+	}
+}
+
+func assertUUIDs(t *testing.T, got []string, want ...string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestOutboxOrderSurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+	pushAll(t, openTestOutbox(t, dir, 0), "a", "b", "c")
+
+	o := openTestOutbox(t, dir, 0)
+	if depth := o.depth(); depth != 3 {
+		t.Fatalf("depth after reopen = %d, want 3", depth)
+	}
+	pushAll(t, o, "d")
+	assertUUIDs(t, drain(t, o), "a", "b", "c", "d")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("%d files left after draining", len(entries))
+	}
+}
+
+func TestOutboxCapacityDropsOldest(t *testing.T) {
+	o := openTestOutbox(t, t.TempDir(), 0)
+	pushAll(t, o, "a")
+	// room for two records of the same size
+	o.maxBytes = 2 * o.bytes
+
+	pushAll(t, o, "b", "c")
+	stats := o.stats()
+	if stats.Depth != 2 || stats.Dropped != 1 {
+		t.Fatalf("stats = %+v, want depth 2 and 1 dropped", stats)
+	}
+	assertUUIDs(t, drain(t, o), "b", "c")
+
+	if err := o.push(&Status{Uuid: string(make([]byte, o.maxBytes))}); err == nil {
+		t.Fatal("push of a record larger than the outbox succeeded")
+	}
+}
+
+func TestOutboxSkipsUnreadableRecords(t *testing.T) {
+	o := openTestOutbox(t, t.TempDir(), 0)
+	pushAll(t, o, "a", "b")
+	if err := os.WriteFile(o.path(o.records[0].seq), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	assertUUIDs(t, drain(t, o), "b")
+	if dropped := o.stats().Dropped; dropped != 1 {
+		t.Fatalf("dropped = %d, want 1", dropped)
+	}
+}
+
+func TestOutboxReject(t *testing.T) {
+	o := openTestOutbox(t, t.TempDir(), 0)
+	pushAll(t, o, "a", "b")
+
+	_, seq, err := o.peek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.reject(seq); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	// a stale sequence number leaves the new head in place
+	if err := o.reject(seq); err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+
+	stats := o.stats()
+	if stats.Depth != 1 || stats.Rejected != 1 {
+		t.Fatalf("stats = %+v, want depth 1 and 1 rejected", stats)
+	}
+	assertUUIDs(t, drain(t, o), "b")
+}
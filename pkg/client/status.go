@@ -2,15 +2,21 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"time"
 
 	status_service "github.com/MagicRodri/grpc_with_go/pkg/generated/status"
 	"github.com/MagicRodri/grpc_with_go/pkg/logger"
 	"github.com/MagicRodri/grpc_with_go/pkg/manager"
+	"github.com/MagicRodri/grpc_with_go/pkg/schema"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	grpcstatus "google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -19,6 +25,18 @@ type StatusClient struct {
 	client status_service.StatusServiceClient
 	log    logger.LoggerInterface
 	cfg    *StatusServiceConfig
+
+	outbox *outbox
+	// outboxMutex is held for reading by direct sends and for writing while a
+	// queued status is replayed, so no status overtakes the queue.
+	outboxMutex sync.RWMutex
+	outboxWake  chan struct{}
+	stopReplay  context.CancelFunc
+	replayDone  chan struct{}
+
+	asyncMutex sync.Mutex
+	async      *asyncSender
+	closed     bool
 }
 
 type Status struct {
@@ -26,21 +44,60 @@ type Status struct {
 	Timestamp float64
 }
 
+// StatusComponent is the logger component of the status client, whose level
+// can be set in logger.components and changed at runtime.
+const StatusComponent = "client.status"
+
+// NewStatusClient creates a client for cfg that logs to log, or to the
+// StatusComponent logger when log is nil. Zero settings take the default
+// tags of StatusServiceConfig. The caller's cfg is not modified.
 func NewStatusClient(log logger.LoggerInterface, cfg *StatusServiceConfig) *StatusClient {
+	if log == nil {
+		log = logger.Component(StatusComponent)
+	}
+	withDefaults := *cfg
+	_ = schema.SetDefaults(&withDefaults) // the default tags of StatusServiceConfig always parse
 	return &StatusClient{
 		log: log,
-		cfg: cfg,
+		cfg: &withDefaults,
 	}
 }
 
 func (sc *StatusClient) Initialize(conn *grpc.ClientConn) error {
 	sc.conn = conn
 	sc.client = status_service.NewStatusServiceClient(conn)
-	sc.log.Info("Statistics gRPC client initialized successfully")
+
+	if sc.cfg.Outbox.Enabled {
+		ob, err := openOutbox(&sc.cfg.Outbox)
+		if err != nil {
+			return fmt.Errorf("failed to open status outbox: %w", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		sc.outbox = ob
+		sc.outboxWake = make(chan struct{}, 1)
+		sc.stopReplay = cancel
+		sc.replayDone = make(chan struct{})
+		go sc.replayOutbox(ctx)
+	}
+
+	sc.log.Info("Statistics gRPC client initialized successfully", logger.Client(sc.cfg.Name))
 	return nil
 }
 
+// Close sends the statuses still queued by SendStatusAsync, then closes the
+// connection.
 func (sc *StatusClient) Close() error {
+	sc.asyncMutex.Lock()
+	sc.closed = true
+	async := sc.async
+	sc.asyncMutex.Unlock()
+	if async != nil {
+		async.close()
+	}
+	if sc.stopReplay != nil {
+		sc.stopReplay()
+		<-sc.replayDone
+	}
 	if sc.conn != nil {
 		return sc.conn.Close()
 	}
@@ -55,12 +112,88 @@ func (sc *StatusClient) GetHost() string {
 	return sc.cfg.Host
 }
 
+// SendStatusMessage records the status. When the outbox is enabled, statuses that
+// fail with a transient error (see retryable) are persisted and ErrStatusQueued is
+// returned; statuses sent while older ones are still queued are appended to the
+// outbox to keep the order. Permanent errors are returned as is.
 func (sc *StatusClient) SendStatusMessage(status *Status) error {
 	if sc.client == nil {
 		return fmt.Errorf("statistics gRPC client is not initialized")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(sc.cfg.Timeout)*time.Second)
+	if sc.outbox == nil {
+		return sc.send(context.Background(), status)
+	}
+
+	sc.outboxMutex.RLock()
+	defer sc.outboxMutex.RUnlock()
+
+	if sc.outbox.depth() > 0 {
+		return sc.enqueue(status, nil)
+	}
+
+	if err := sc.send(context.Background(), status); err != nil {
+		if retryable(err) {
+			return sc.enqueue(status, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// retryable reports whether a failed send may succeed later and is worth queuing.
+func retryable(err error) bool {
+	switch grpcstatus.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// SendStatusAsync queues the status for delivery by the worker pool and returns
+// immediately. What happens when the queue is full depends on the configured
+// overflow policy; dropped statuses complete their future with ErrSendDropped.
+func (sc *StatusClient) SendStatusAsync(status *Status) *SendFuture {
+	sc.asyncMutex.Lock()
+	if sc.closed {
+		sc.asyncMutex.Unlock()
+		future := newSendFuture()
+		future.complete(ErrSenderClosed)
+		return future
+	}
+	if sc.async == nil {
+		sc.async = newAsyncSender(&sc.cfg.Async, sc.SendStatusMessage)
+	}
+	async := sc.async
+	sc.asyncMutex.Unlock()
+
+	return async.submit(status)
+}
+
+// Flush waits until all statuses passed to SendStatusAsync have been handled.
+func (sc *StatusClient) Flush(ctx context.Context) error {
+	sc.asyncMutex.Lock()
+	async := sc.async
+	sc.asyncMutex.Unlock()
+
+	if async == nil {
+		return nil
+	}
+	return async.flush(ctx)
+}
+
+// OutboxStats reports the depth and drop counts of the outbox.
+// It returns zero values when the outbox is disabled.
+func (sc *StatusClient) OutboxStats() OutboxStats {
+	if sc.outbox == nil {
+		return OutboxStats{}
+	}
+	return sc.outbox.stats()
+}
+
+func (sc *StatusClient) send(ctx context.Context, status *Status) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(sc.cfg.Timeout)*time.Second)
 	defer cancel()
 
 	req := &status_service.StatusMessage{
@@ -70,14 +203,108 @@ func (sc *StatusClient) SendStatusMessage(status *Status) error {
 
 	res, err := sc.client.SetStatus(ctx, req)
 	if err != nil {
-		sc.log.Error("failed to record status: %v", err)
+		sc.log.Error("failed to record status", logger.Client(sc.cfg.Name), "uuid", status.Uuid, logger.Err(err))
 		return fmt.Errorf("failed to record status: %w", err)
 	}
 
-	sc.log.Debug("Successfully recorded status: %+v, response: %+v", status, res)
+	sc.log.Debug("Successfully recorded status", logger.Client(sc.cfg.Name), "uuid", status.Uuid, "response", res.GetMessage())
 	return nil
 }
 
+func (sc *StatusClient) enqueue(status *Status, cause error) error {
+	if err := sc.outbox.push(status); err != nil {
+		return errors.Join(fmt.Errorf("failed to queue status: %w", err), cause)
+	}
+
+	select {
+	case sc.outboxWake <- struct{}{}:
+	default:
+	}
+
+	if cause != nil {
+		return fmt.Errorf("%w: %w", ErrStatusQueued, cause)
+	}
+	return ErrStatusQueued
+}
+
+// replayOutbox drains the outbox whenever the connection is READY, re-checking
+// on every connectivity change and every newly queued status.
+func (sc *StatusClient) replayOutbox(ctx context.Context) {
+	defer close(sc.replayDone)
+
+	for {
+		state := sc.conn.GetState()
+		if sc.outbox.depth() > 0 {
+			switch state {
+			case connectivity.Ready:
+				sc.drainOutbox(ctx)
+			case connectivity.Idle:
+				sc.conn.Connect()
+			}
+		}
+
+		waitCtx, cancel := context.WithCancel(ctx)
+		changed := make(chan struct{})
+		go func() {
+			sc.conn.WaitForStateChange(waitCtx, state)
+			close(changed)
+		}()
+
+		select {
+		case <-ctx.Done():
+		case <-changed:
+		case <-sc.outboxWake:
+		}
+		cancel()
+		<-changed
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (sc *StatusClient) drainOutbox(ctx context.Context) {
+	for ctx.Err() == nil {
+		if !sc.replayHead(ctx) {
+			return
+		}
+	}
+}
+
+// replayHead sends the oldest queued status and reports whether the next one
+// should follow. A status refused with a permanent error is dropped, so it does
+// not block the queue.
+func (sc *StatusClient) replayHead(ctx context.Context) bool {
+	sc.outboxMutex.Lock()
+	defer sc.outboxMutex.Unlock()
+
+	status, seq, err := sc.outbox.peek()
+	if err != nil {
+		sc.log.Error("failed to read status outbox", logger.Client(sc.cfg.Name), logger.Err(err))
+		return false
+	}
+	if status == nil {
+		return false
+	}
+
+	err = sc.send(ctx, status)
+	switch {
+	case err == nil:
+		err = sc.outbox.pop(seq)
+	case ctx.Err() != nil || retryable(err):
+		sc.log.Warn("failed to replay queued status, will retry", logger.Client(sc.cfg.Name), logger.Err(err))
+		return false
+	default:
+		sc.log.Error("queued status rejected, dropping it", logger.Client(sc.cfg.Name), "uuid", status.Uuid, logger.Err(err))
+		err = sc.outbox.reject(seq)
+	}
+	if err != nil {
+		sc.log.Error("failed to update status outbox", logger.Client(sc.cfg.Name), logger.Err(err))
+	}
+	return true
+}
+
 // helper function to get the StatusClient from the global client manager
 func GetStatusClient(name string) (*StatusClient, error) {
 	if manager.GlobalGrpcClientManager == nil {
@@ -0,0 +1,247 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	status_service "github.com/MagicRodri/grpc_with_go/pkg/generated/status"
+	"github.com/MagicRodri/grpc_with_go/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// statusServer records the delivered statuses and fails the others with the
+// error returned by fail.
+type statusServer struct {
+	status_service.UnimplementedStatusServiceServer
+
+	mutex     sync.Mutex
+	fail      func(uuid string) error
+	delivered []string
+}
+
+func (s *statusServer) SetStatus(_ context.Context, in *status_service.StatusMessage) (*status_service.StatusResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.fail != nil {
+		if err := s.fail(in.GetUuid()); err != nil {
+			return nil, err
+		}
+	}
+	s.delivered = append(s.delivered, in.GetUuid())
+	return &status_service.StatusResponse{Uuid: in.GetUuid()}, nil
+}
+
+func (s *statusServer) setFail(fail func(uuid string) error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.fail = fail
+}
+
+func (s *statusServer) received() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.delivered...)
+}
+
+func failAll(code codes.Code) func(string) error {
+	return func(string) error { return grpcstatus.Error(code, "failed") }
+}
+
+func newTestStatusClient(t *testing.T, srv *statusServer) *StatusClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	status_service.RegisterStatusServiceServer(server, srv)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := NewStatusClient(logger.Default(), &StatusServiceConfig{
+		Host:   "bufconn",
+		Outbox: OutboxConfig{Enabled: true, Dir: t.TempDir()},
+	})
+	if err := sc.Initialize(conn); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sc.Close() })
+	return sc
+}
+
+func waitForEmptyOutbox(t *testing.T, sc *StatusClient) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for sc.OutboxStats().Depth > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("outbox not drained: %+v", sc.OutboxStats())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSendStatusPermanentErrorIsNotQueued(t *testing.T) {
+	srv := &statusServer{fail: failAll(codes.InvalidArgument)}
+	sc := newTestStatusClient(t, srv)
+
+	err := sc.SendStatusMessage(&Status{Uuid: "a"})
+	if err == nil || errors.Is(err, ErrStatusQueued) {
+		t.Fatalf("SendStatusMessage = %v, want a permanent error", err)
+	}
+	if code := grpcstatus.Code(err); code != codes.InvalidArgument {
+		t.Fatalf("code = %v, want InvalidArgument", code)
+	}
+	if depth := sc.OutboxStats().Depth; depth != 0 {
+		t.Fatalf("depth = %d, want 0", depth)
+	}
+
+	srv.setFail(nil)
+	if err := sc.SendStatusMessage(&Status{Uuid: "b"}); err != nil {
+		t.Fatalf("SendStatusMessage after a permanent error: %v", err)
+	}
+}
+
+func TestSendStatusTransientErrorIsReplayedInOrder(t *testing.T) {
+	for _, code := range []codes.Code{codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted} {
+		t.Run(code.String(), func(t *testing.T) {
+			srv := &statusServer{fail: failAll(code)}
+			sc := newTestStatusClient(t, srv)
+
+			if err := sc.SendStatusMessage(&Status{Uuid: "a"}); !errors.Is(err, ErrStatusQueued) {
+				t.Fatalf("SendStatusMessage = %v, want ErrStatusQueued", err)
+			}
+
+			srv.setFail(nil)
+			// queued behind "a" even though the server is back
+			if err := sc.SendStatusMessage(&Status{Uuid: "b"}); !errors.Is(err, ErrStatusQueued) {
+				t.Fatalf("SendStatusMessage = %v, want ErrStatusQueued", err)
+			}
+			waitForEmptyOutbox(t, sc)
+			assertUUIDs(t, srv.received(), "a", "b")
+		})
+	}
+}
+
+func TestReplayDropsRejectedStatus(t *testing.T) {
+	srv := &statusServer{fail: failAll(codes.Unavailable)}
+	sc := newTestStatusClient(t, srv)
+
+	for _, uuid := range []string{"bad", "good"} {
+		if err := sc.SendStatusMessage(&Status{Uuid: uuid}); !errors.Is(err, ErrStatusQueued) {
+			t.Fatalf("SendStatusMessage(%s) = %v, want ErrStatusQueued", uuid, err)
+		}
+	}
+
+	srv.setFail(func(uuid string) error {
+		if uuid == "bad" {
+			return grpcstatus.Error(codes.InvalidArgument, "bad status")
+		}
+		return nil
+	})
+	sc.SendStatusMessage(&Status{Uuid: "next"})
+	waitForEmptyOutbox(t, sc)
+
+	assertUUIDs(t, srv.received(), "good", "next")
+	if rejected := sc.OutboxStats().Rejected; rejected != 1 {
+		t.Fatalf("rejected = %d, want 1", rejected)
+	}
+}
+
+// holdStatuses makes srv wait for the returned channel to close before it
+// handles a status.
+func holdStatuses(srv *statusServer) chan struct{} {
+	release := make(chan struct{})
+	srv.setFail(func(string) error {
+		<-release
+		return nil
+	})
+	return release
+}
+
+func TestStatusClientFlushDrainsAsyncQueue(t *testing.T) {
+	srv := &statusServer{}
+	sc := newTestStatusClient(t, srv)
+	sc.cfg.Async = AsyncConfig{Workers: 1}
+	release := holdStatuses(srv)
+
+	var futures []*SendFuture
+	for _, uuid := range []string{"a", "b", "c"} {
+		futures = append(futures, sc.SendStatusAsync(&Status{Uuid: uuid}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := sc.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Flush with the server held = %v, want DeadlineExceeded", err)
+	}
+
+	close(release)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sc.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	for i, future := range futures {
+		if err := future.Err(); err != nil {
+			t.Errorf("future %d = %v, want nil", i, err)
+		}
+	}
+	assertUUIDs(t, srv.received(), "a", "b", "c")
+}
+
+func TestStatusClientCloseDrainsAsyncQueue(t *testing.T) {
+	srv := &statusServer{}
+	sc := newTestStatusClient(t, srv)
+	sc.cfg.Async = AsyncConfig{Workers: 1}
+	release := holdStatuses(srv)
+
+	var futures []*SendFuture
+	for _, uuid := range []string{"a", "b", "c"} {
+		futures = append(futures, sc.SendStatusAsync(&Status{Uuid: uuid}))
+	}
+
+	closed := make(chan error)
+	go func() { closed <- sc.Close() }()
+	select {
+	case err := <-closed:
+		t.Fatalf("Close returned with statuses queued: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return")
+	}
+	for i, future := range futures {
+		if err := future.Err(); err != nil {
+			t.Errorf("future %d = %v, want nil", i, err)
+		}
+	}
+	assertUUIDs(t, srv.received(), "a", "b", "c")
+	if err := sc.SendStatusAsync(&Status{Uuid: "late"}).Err(); !errors.Is(err, ErrSenderClosed) {
+		t.Fatalf("SendStatusAsync after Close = %v, want ErrSenderClosed", err)
+	}
+}
@@ -7,6 +7,7 @@
 package helloworld
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -116,14 +117,14 @@ var File_protos_helloworld_proto protoreflect.FileDescriptor
 const file_protos_helloworld_proto_rawDesc = "" +
 	"\n" +
 	"\x17protos/helloworld.proto\x12\n" +
-	"helloworld\"\"\n" +
+	"helloworld\x1a\x1cgoogle/api/annotations.proto\"\"\n" +
 	"\fHelloRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\"&\n" +
 	"\n" +
 	"HelloReply\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage2I\n" +
-	"\aGreeter\x12>\n" +
-	"\bSayHello\x12\x18.helloworld.HelloRequest\x1a\x16.helloworld.HelloReply\"\x00B\rZ\v/helloworldb\x06proto3"
+	"\amessage\x18\x01 \x01(\tR\amessage2]\n" +
+	"\aGreeter\x12R\n" +
+	"\bSayHello\x12\x18.helloworld.HelloRequest\x1a\x16.helloworld.HelloReply\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/helloB=Z;github.com/MagicRodri/grpc_with_go/pkg/generated/helloworldb\x06proto3"
 
 var (
 	file_protos_helloworld_proto_rawDescOnce sync.Once
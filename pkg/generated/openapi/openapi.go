@@ -0,0 +1,8 @@
+// Package openapi embeds the OpenAPI description of the REST gateway,
+// generated from the HTTP annotations of the protos by make proto.
+package openapi
+
+import _ "embed"
+
+//go:embed api.swagger.json
+var Spec []byte
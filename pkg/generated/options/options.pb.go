@@ -0,0 +1,84 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.1
+// source: protos/options.proto
+
+package options
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var file_protos_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         50000,
+		Name:          "options.sensitive",
+		Tag:           "varint,50000,opt,name=sensitive",
+		Filename:      "protos/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// Marks a field whose value must never be written to logs.
+	//
+	// optional bool sensitive = 50000;
+	E_Sensitive = &file_protos_options_proto_extTypes[0]
+)
+
+var File_protos_options_proto protoreflect.FileDescriptor
+
+const file_protos_options_proto_rawDesc = "" +
+	"\n" +
+	"\x14protos/options.proto\x12\aoptions\x1a google/protobuf/descriptor.proto:=\n" +
+	"\tsensitive\x12\x1d.google.protobuf.FieldOptions\x18І\x03 \x01(\bR\tsensitiveB:Z8github.com/MagicRodri/grpc_with_go/pkg/generated/optionsb\x06proto3"
+
+var file_protos_options_proto_goTypes = []any{
+	(*descriptorpb.FieldOptions)(nil), // 0: google.protobuf.FieldOptions
+}
+var file_protos_options_proto_depIdxs = []int32{
+	0, // 0: options.sensitive:extendee -> google.protobuf.FieldOptions
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_protos_options_proto_init() }
+func file_protos_options_proto_init() {
+	if File_protos_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protos_options_proto_rawDesc), len(file_protos_options_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_protos_options_proto_goTypes,
+		DependencyIndexes: file_protos_options_proto_depIdxs,
+		ExtensionInfos:    file_protos_options_proto_extTypes,
+	}.Build()
+	File_protos_options_proto = out.File
+	file_protos_options_proto_goTypes = nil
+	file_protos_options_proto_depIdxs = nil
+}
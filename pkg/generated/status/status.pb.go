@@ -7,6 +7,7 @@
 package status
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
@@ -182,7 +183,7 @@ var File_protos_status_proto protoreflect.FileDescriptor
 
 const file_protos_status_proto_rawDesc = "" +
 	"\n" +
-	"\x13protos/status.proto\x12\x06status\x1a\x1fgoogle/protobuf/timestamp.proto\"]\n" +
+	"\x13protos/status.proto\x12\x06status\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"]\n" +
 	"\rStatusMessage\x12\x12\n" +
 	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x128\n" +
 	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"#\n" +
@@ -191,11 +192,11 @@ const file_protos_status_proto_rawDesc = "" +
 	"\x0eStatusResponse\x12\x12\n" +
 	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x12\n" +
-	"\x04code\x18\x03 \x01(\x05R\x04code2\xc6\x01\n" +
-	"\rStatusService\x12:\n" +
-	"\tGetStatus\x12\x15.status.StatusRequest\x1a\x16.status.StatusResponse\x12:\n" +
-	"\tSetStatus\x12\x15.status.StatusMessage\x1a\x16.status.StatusResponse\x12=\n" +
-	"\fDeleteStatus\x12\x15.status.StatusRequest\x1a\x16.status.StatusResponseB\tZ\a/statusb\x06proto3"
+	"\x04code\x18\x03 \x01(\x05R\x04code2\x9a\x02\n" +
+	"\rStatusService\x12U\n" +
+	"\tGetStatus\x12\x15.status.StatusRequest\x1a\x16.status.StatusResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/status/{uuid}\x12X\n" +
+	"\tSetStatus\x12\x15.status.StatusMessage\x1a\x16.status.StatusResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\x1a\x11/v1/status/{uuid}\x12X\n" +
+	"\fDeleteStatus\x12\x15.status.StatusRequest\x1a\x16.status.StatusResponse\"\x19\x82\xd3\xe4\x93\x02\x13*\x11/v1/status/{uuid}B9Z7github.com/MagicRodri/grpc_with_go/pkg/generated/statusb\x06proto3"
 
 var (
 	file_protos_status_proto_rawDescOnce sync.Once
@@ -0,0 +1,13 @@
+package logger
+
+import "log/slog"
+
+// Err атрибут с ошибкой
+func Err(err error) slog.Attr {
+	return slog.Any("error", err)
+}
+
+// Client атрибут с именем gRPC клиента
+func Client(name string) slog.Attr {
+	return slog.String("client", name)
+}
@@ -1,12 +1,109 @@
 package logger
 
-import "github.com/MagicRodri/grpc_with_go/pkg/validation"
+import (
+	"time"
 
+	"github.com/MagicRodri/grpc_with_go/pkg/validation"
+)
+
+// Config настройки логгера. Поля верхнего уровня описывают единственный sink,
+// если список Sinks пуст. Level задает уровень по умолчанию, Components -
+// уровни именованных компонентов (manager, grpc.server, client.status).
 type Config struct {
-	Level  string `mapstructure:"level"`
-	Path   string `mapstructure:"path" validate:"omitempty,filepath"`
-	Format string `mapstructure:"format" validate:"oneof=json text"`
-	Output string `mapstructure:"output" validate:"oneof=stdout file"`
+	SinkConfig `mapstructure:",squash"`
+
+	Sinks      []SinkConfig      `mapstructure:"sinks" validate:"dive" desc:"Log destinations; when set, the top-level sink settings are ignored"`
+	Components map[string]string `mapstructure:"components" validate:"dive,keys,required,endkeys,oneof=debug info warn error" desc:"Levels of named components such as manager or grpc.server"`
+	Redact     RedactConfig      `mapstructure:"redact" desc:"Masking of sensitive attributes"`
+	Sampling   SamplingConfig    `mapstructure:"sampling" desc:"Sampling of repeated messages"`
+	RingBuffer RingBufferConfig  `mapstructure:"ring_buffer" desc:"In-memory buffer of recent records served over the admin API"`
+}
+
+// RingBufferConfig хранение последних Size записей в памяти для просмотра через
+// admin RPC; 0 - выключено. Level дополнительно ограничивает уровень записей
+type RingBufferConfig struct {
+	Size  int    `mapstructure:"size" validate:"gte=0" desc:"Number of records kept, 0 disables the buffer"`
+	Level string `mapstructure:"level" validate:"omitempty,oneof=debug info warn error" desc:"Minimum level of buffered records, all records by default"`
+}
+
+// SamplingConfig настройки семплирования одинаковых сообщений по уровням.
+// Уровни, отсутствующие в Levels, не семплируются; Interval по умолчанию 1s
+type SamplingConfig struct {
+	Interval time.Duration            `mapstructure:"interval" validate:"duration" default:"1s" desc:"Window in which identical messages are counted"`
+	Levels   map[string]LevelSampling `mapstructure:"levels" validate:"dive,keys,oneof=debug info warn error,endkeys" desc:"Sampling per level; levels not listed are not sampled"`
+}
+
+// LevelSampling первые First сообщений за интервал пропускаются, затем каждое
+// Thereafter-е; Thereafter 0 отбрасывает все остальные
+type LevelSampling struct {
+	First      int `mapstructure:"first" validate:"gte=0" desc:"Messages passed in each interval before sampling starts"`
+	Thereafter int `mapstructure:"thereafter" validate:"gte=0" desc:"Pass every Nth message after the first ones, 0 drops them"`
+}
+
+// RedactConfig настройки скрытия чувствительных данных. Keys - шаблоны ключей
+// в формате path.Match без учета регистра, дополняющие DefaultRedactKeys
+type RedactConfig struct {
+	Keys            []string `mapstructure:"keys" validate:"dive,required" desc:"Case-insensitive path.Match patterns of attribute keys to mask"`
+	Mask            string   `mapstructure:"mask" default:"[REDACTED]" desc:"Replacement of masked values"`
+	DisableDefaults bool     `mapstructure:"disable_defaults" desc:"Mask only the configured keys, not the built-in ones"`
+}
+
+// SinkConfig настройки одного назначения логов
+type SinkConfig struct {
+	Level  string `mapstructure:"level" validate:"omitempty,oneof=debug info warn error" desc:"Default level at the top level (info when empty); minimum level of a sink in sinks"`
+	Path   string `mapstructure:"path" validate:"required_if=Output file,omitempty,filepath" desc:"Log file of the file output"`
+	Format string `mapstructure:"format" validate:"omitempty,oneof=json text console" default:"json" desc:"Record format"`
+	Output string `mapstructure:"output" validate:"omitempty,oneof=stdout stderr file syslog http" desc:"Destination, stdout or file when path is set by default"`
+	Color  string `mapstructure:"color" validate:"omitempty,oneof=auto always never" default:"auto" desc:"Colors of the console format; auto colors a terminal unless NO_COLOR is set"`
+
+	Rotation RotationConfig `mapstructure:"rotation" desc:"Rotation of the file output"`
+	Syslog   SyslogConfig   `mapstructure:"syslog" desc:"Settings of the syslog output"`
+	HTTP     HTTPSinkConfig `mapstructure:"http" desc:"Settings of the http output"`
+}
+
+// SyslogConfig настройки отправки в syslog по RFC 5424.
+// Network по умолчанию udp, Facility - user, AppName - имя исполняемого файла
+type SyslogConfig struct {
+	Network  string `mapstructure:"network" validate:"omitempty,oneof=udp tcp unix unixgram" default:"udp" desc:"Network of the syslog collector"`
+	Address  string `mapstructure:"address" desc:"Address of the syslog collector"`
+	Facility string `mapstructure:"facility" default:"user" desc:"Syslog facility"`
+	AppName  string `mapstructure:"app_name" desc:"APP-NAME of the messages, the executable name by default"`
+}
+
+// HTTPSinkConfig настройки пакетной отправки записей в формате NDJSON POST-запросами
+type HTTPSinkConfig struct {
+	URL           string            `mapstructure:"url" validate:"omitempty,url_scheme=http https" desc:"Collector endpoint receiving NDJSON batches"`
+	BatchSize     int               `mapstructure:"batch_size" validate:"gte=0" default:"100" desc:"Records per request"`
+	FlushInterval time.Duration     `mapstructure:"flush_interval" validate:"duration" default:"1s" desc:"Maximum delay before buffered records are sent"`
+	MaxBuffer     int               `mapstructure:"max_buffer" validate:"gte=0" default:"10000" desc:"Records kept while the collector is unavailable, the oldest are dropped"`
+	Timeout       time.Duration     `mapstructure:"timeout" validate:"duration" default:"5s" desc:"Timeout of one request"`
+	MaxRetries    int               `mapstructure:"max_retries" validate:"gte=-1" default:"3" desc:"Retries of a failed batch before it is dropped; -1 disables retries, 0 means the default"`
+	Headers       map[string]string `mapstructure:"headers" desc:"Extra request headers, e.g. Authorization"`
+}
+
+// RotationConfig настройки ротации файла лога.
+// MaxSize задается в мегабайтах, MaxAge - максимальный возраст текущего файла,
+// MaxBackups - сколько архивов хранить (0 - все). ReopenOnSignal включает
+// переоткрытие файла по SIGHUP для внешнего logrotate.
+type RotationConfig struct {
+	MaxSize        int           `mapstructure:"max_size" validate:"gte=0" desc:"Size in megabytes that triggers rotation, 0 disables it"`
+	MaxAge         time.Duration `mapstructure:"max_age" validate:"duration" desc:"Age of the current file that triggers rotation, 0 disables it"`
+	MaxBackups     int           `mapstructure:"max_backups" validate:"gte=0" desc:"Rotated files to keep, 0 keeps all"`
+	Compress       bool          `mapstructure:"compress" desc:"Gzip rotated files"`
+	ReopenOnSignal bool          `mapstructure:"reopen_on_signal" desc:"Reopen the file on SIGHUP for an external logrotate"`
+}
+
+func init() {
+	// настройки выбранного назначения обязательны
+	validation.RegisterStructRule(func(cfg SinkConfig) []validation.Violation {
+		switch {
+		case cfg.Output == "syslog" && cfg.Syslog.Address == "":
+			return []validation.Violation{{Field: "syslog.address", Tag: "required_if", Param: "Output syslog"}}
+		case cfg.Output == "http" && cfg.HTTP.URL == "":
+			return []validation.Violation{{Field: "http.url", Tag: "required_if", Param: "Output http"}}
+		}
+		return nil
+	})
 }
 
 func (cfg *Config) Validate() error {
@@ -0,0 +1,206 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorDim    = "\033[2m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorBlue   = "\033[34m"
+	colorCyan   = "\033[36m"
+)
+
+// consoleHandler человекочитаемый цветной вывод для локальной разработки:
+//
+//	15:04:05.000 INF message key=value file.go:42
+type consoleHandler struct {
+	opts   slog.HandlerOptions
+	color  bool
+	prefix string
+	attrs  []byte
+
+	mutex *sync.Mutex
+	w     io.Writer
+}
+
+// newConsoleHandler создает обработчик. color: always, never или auto (по умолчанию) -
+// цвет только для терминала и без переменной NO_COLOR
+func newConsoleHandler(w io.Writer, color string, opts *slog.HandlerOptions) *consoleHandler {
+	h := &consoleHandler{
+		color: useColor(w, color),
+		mutex: &sync.Mutex{},
+		w:     w,
+	}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+func useColor(w io.Writer, color string) bool {
+	switch color {
+	case "always":
+		return true
+	case "never":
+		return false
+	default:
+		return os.Getenv("NO_COLOR") == "" && isTerminal(w)
+	}
+}
+
+// isTerminal сообщает, является ли w символьным устройством (терминалом)
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	buf := &bytes.Buffer{}
+
+	if !r.Time.IsZero() {
+		h.paint(buf, colorDim, r.Time.Format("15:04:05.000"))
+		buf.WriteByte(' ')
+	}
+	h.writeLevel(buf, r.Level)
+	buf.WriteByte(' ')
+	buf.WriteString(r.Message)
+
+	buf.Write(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		h.appendAttr(buf, h.prefix, a)
+		return true
+	})
+
+	if h.opts.AddSource && r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := frames.Next()
+		buf.WriteByte(' ')
+		h.paint(buf, colorDim, filepath.Base(f.File)+":"+strconv.Itoa(f.Line))
+	}
+	buf.WriteByte('\n')
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	buf := bytes.NewBuffer(append([]byte(nil), h.attrs...))
+	for _, a := range attrs {
+		clone.appendAttr(buf, h.prefix, a)
+	}
+	clone.attrs = buf.Bytes()
+	return &clone
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+func (h *consoleHandler) appendAttr(buf *bytes.Buffer, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(groupsOf(prefix), a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			h.appendAttr(buf, groupPrefix, ga)
+		}
+		return
+	}
+
+	buf.WriteByte(' ')
+	h.paint(buf, colorCyan, prefix+a.Key+"=")
+	buf.WriteString(consoleValue(a.Value))
+}
+
+func (h *consoleHandler) writeLevel(buf *bytes.Buffer, level slog.Level) {
+	switch {
+	case level >= slog.LevelError:
+		h.paint(buf, colorRed, "ERR")
+	case level >= slog.LevelWarn:
+		h.paint(buf, colorYellow, "WRN")
+	case level >= slog.LevelInfo:
+		h.paint(buf, colorBlue, "INF")
+	default:
+		h.paint(buf, colorDim, "DBG")
+	}
+}
+
+func (h *consoleHandler) paint(buf *bytes.Buffer, color, s string) {
+	if !h.color {
+		buf.WriteString(s)
+		return
+	}
+	buf.WriteString(color)
+	buf.WriteString(s)
+	buf.WriteString(colorReset)
+}
+
+func consoleValue(v slog.Value) string {
+	var s string
+	switch v.Kind() {
+	case slog.KindString:
+		s = v.String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339)
+	default:
+		s = fmt.Sprint(v.Any())
+	}
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func groupsOf(prefix string) []string {
+	if prefix == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(prefix, "."), ".")
+}
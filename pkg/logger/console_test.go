@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestConsoleHandlerFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := slog.New(newConsoleHandler(buf, "never", &slog.HandlerOptions{AddSource: true}))
+
+	log.With("service", "api").WithGroup("req").Info("hello world", "id", 1, "note", "a b", "empty", "")
+
+	line := buf.String()
+	want := regexp.MustCompile(`^\d\d:\d\d:\d\d\.\d{3} INF hello world service=api req\.id=1 req\.note="a b" req\.empty="" console_test\.go:\d+\n$`)
+	if !want.MatchString(line) {
+		t.Fatalf("line = %q", line)
+	}
+}
+
+func TestConsoleHandlerLevels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := slog.New(newConsoleHandler(buf, "never", &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	log.Debug("d")
+	log.Info("i")
+	log.Warn("w")
+	log.Error("e")
+
+	var levels []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		levels = append(levels, strings.Fields(line)[1])
+	}
+	if got := strings.Join(levels, " "); got != "DBG INF WRN ERR" {
+		t.Fatalf("levels = %s", got)
+	}
+
+	buf.Reset()
+	log = slog.New(newConsoleHandler(buf, "never", &slog.HandlerOptions{Level: slog.LevelWarn}))
+	log.Info("filtered")
+	if buf.Len() != 0 {
+		t.Fatalf("info below warn written: %q", buf.String())
+	}
+}
+
+func TestConsoleColor(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	tests := []struct {
+		name    string
+		color   string
+		file    bool
+		noColor string
+		want    bool
+	}{
+		{name: "always", color: "always", want: true},
+		{name: "always ignores NO_COLOR", color: "always", noColor: "1", want: true},
+		{name: "never", color: "never"},
+		{name: "auto buffer", color: "auto"},
+		{name: "auto default", color: ""},
+		{name: "auto file", color: "auto", file: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			var got bool
+			if tt.file {
+				got = useColor(file, tt.color)
+			} else {
+				buf := &bytes.Buffer{}
+				slog.New(newConsoleHandler(buf, tt.color, nil)).Info("message", "key", "value")
+				got = strings.Contains(buf.String(), "\033[")
+			}
+			if got != tt.want {
+				t.Fatalf("colored = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFormatHandler(t *testing.T) {
+	tests := []struct {
+		format string
+		want   *regexp.Regexp
+	}{
+		{format: "", want: regexp.MustCompile(`^\{"time":.*"msg":"message","key":"value"\}\n$`)},
+		{format: "json", want: regexp.MustCompile(`^\{"time":.*"msg":"message","key":"value"\}\n$`)},
+		{format: "text", want: regexp.MustCompile(`^time=\S+ level=INFO msg=message key=value\n$`)},
+		{format: "console", want: regexp.MustCompile(`^\S+ INF message key=value\n$`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			handler, err := newFormatHandler(tt.format, "never", buf, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			slog.New(handler).Info("message", "key", "value")
+			if !tt.want.MatchString(buf.String()) {
+				t.Fatalf("output = %q", buf.String())
+			}
+		})
+	}
+
+	if _, err := newFormatHandler("xml", "", &bytes.Buffer{}, nil); err == nil {
+		t.Fatal("newFormatHandler accepted an unknown format")
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	tests := []struct {
+		name    string
+		cfg     SinkConfig
+		want    any
+		wantErr bool
+	}{
+		{name: "default", cfg: SinkConfig{}, want: os.Stdout},
+		{name: "stdout", cfg: SinkConfig{Output: "stdout"}, want: os.Stdout},
+		{name: "stderr", cfg: SinkConfig{Output: "stderr"}, want: os.Stderr},
+		{name: "path implies file", cfg: SinkConfig{Path: path}},
+		{name: "file without path", cfg: SinkConfig{Output: "file"}, wantErr: true},
+		{name: "unknown", cfg: SinkConfig{Output: "kafka"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := newWriter(&tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("newWriter succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != nil {
+				if w != tt.want {
+					t.Fatalf("writer = %v, want %v", w, tt.want)
+				}
+				return
+			}
+			if _, ok := w.(*rotatingFile); !ok {
+				t.Fatalf("writer = %T, want a rotating file", w)
+			}
+			w.(*rotatingFile).Close()
+		})
+	}
+}
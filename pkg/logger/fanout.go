@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// fanoutHandler передает запись во все sink-и, уровень которых ее пропускает
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+func newFanoutHandler(handlers ...slog.Handler) *fanoutHandler {
+	return &fanoutHandler{handlers: handlers}
+}
+
+func (fh *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range fh.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (fh *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range fh.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (fh *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(fh.handlers))
+	for i, h := range fh.handlers {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+func (fh *fanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(fh.handlers))
+	for i, h := range fh.handlers {
+		handlers[i] = h.WithGroup(name)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
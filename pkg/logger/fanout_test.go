@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingHandler accepts every record and fails to write it.
+type failingHandler struct{ err error }
+
+func (h failingHandler) Enabled(context.Context, slog.Level) bool  { return true }
+func (h failingHandler) Handle(context.Context, slog.Record) error { return h.err }
+func (h failingHandler) WithAttrs([]slog.Attr) slog.Handler        { return h }
+func (h failingHandler) WithGroup(string) slog.Handler             { return h }
+
+func newSinkBuffer(level slog.Level) (*bytes.Buffer, slog.Handler) {
+	buf := &bytes.Buffer{}
+	return buf, slog.NewTextHandler(buf, &slog.HandlerOptions{Level: level})
+}
+
+func TestFanoutPerSinkLevel(t *testing.T) {
+	debug, debugSink := newSinkBuffer(slog.LevelDebug)
+	errs, errorSink := newSinkBuffer(slog.LevelError)
+	fanout := newFanoutHandler(debugSink, errorSink)
+	log := slog.New(fanout)
+
+	if fanout.Enabled(context.Background(), slog.LevelDebug-1) {
+		t.Fatal("enabled below the lowest sink level")
+	}
+	log.Debug("details")
+	log.Error("failed")
+
+	if got := debug.String(); !strings.Contains(got, "msg=details") || !strings.Contains(got, "msg=failed") {
+		t.Fatalf("debug sink = %q, want both records", got)
+	}
+	if got := errs.String(); strings.Contains(got, "msg=details") || !strings.Contains(got, "msg=failed") {
+		t.Fatalf("error sink = %q, want only the error", got)
+	}
+}
+
+func TestFanoutAttrsGroupsAndContextFieldsReachEverySink(t *testing.T) {
+	a, sinkA := newSinkBuffer(slog.LevelInfo)
+	b, sinkB := newSinkBuffer(slog.LevelInfo)
+	log := slog.New(newContextHandler(newFanoutHandler(sinkA, sinkB)))
+
+	ctx := AppendCtx(context.Background(), "request_id", "r1")
+	log.With("service", "api").WithGroup("req").InfoContext(ctx, "handled", "status", 200)
+
+	for name, buf := range map[string]*bytes.Buffer{"a": a, "b": b} {
+		got := buf.String()
+		for _, want := range []string{"service=api", "req.status=200", "req.request_id=r1"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("sink %s = %q, want %s", name, got, want)
+			}
+		}
+	}
+}
+
+func TestFanoutSinkErrorDoesNotStopOthers(t *testing.T) {
+	errSink := errors.New("sink failed")
+	before, sinkBefore := newSinkBuffer(slog.LevelInfo)
+	after, sinkAfter := newSinkBuffer(slog.LevelInfo)
+	fanout := newFanoutHandler(sinkBefore, failingHandler{err: errSink}, sinkAfter)
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "message", 0)
+	if err := fanout.Handle(context.Background(), r); !errors.Is(err, errSink) {
+		t.Fatalf("Handle = %v, want the sink error", err)
+	}
+	if before.Len() == 0 || after.Len() == 0 {
+		t.Fatalf("sinks = %q, %q, want the record in both", before.String(), after.String())
+	}
+}
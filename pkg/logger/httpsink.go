@@ -0,0 +1,237 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHTTPBatchSize     = 100
+	defaultHTTPFlushInterval = time.Second
+	defaultHTTPMaxBuffer     = 10000
+	defaultHTTPTimeout       = 5 * time.Second
+	defaultHTTPMaxRetries    = 3
+	httpInitialBackoff       = 100 * time.Millisecond
+	httpMaxBackoff           = 10 * time.Second
+	// httpCloseTimeout общее время на отправку оставшихся записей при закрытии
+	httpCloseTimeout = 10 * time.Second
+)
+
+// httpWriter копит записи в памяти и отправляет их пачками в формате NDJSON.
+// При переполнении буфера отбрасываются самые старые записи, неудачная
+// отправка повторяется с экспоненциальной задержкой. MaxRetries -1 отключает
+// повторы, 0 означает значение по умолчанию
+type httpWriter struct {
+	cfg    HTTPSinkConfig
+	client *http.Client
+	// ctx отменяется, когда истекает время на закрытие
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex   sync.Mutex
+	lines   [][]byte
+	dropped uint64
+
+	flush chan struct{}
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newHTTPWriter(cfg *HTTPSinkConfig) (*httpWriter, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("url is required for http output")
+	}
+
+	w := &httpWriter{
+		cfg:   *cfg,
+		flush: make(chan struct{}, 1),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	if w.cfg.BatchSize <= 0 {
+		w.cfg.BatchSize = defaultHTTPBatchSize
+	}
+	if w.cfg.FlushInterval <= 0 {
+		w.cfg.FlushInterval = defaultHTTPFlushInterval
+	}
+	if w.cfg.MaxBuffer <= 0 {
+		w.cfg.MaxBuffer = defaultHTTPMaxBuffer
+	}
+	if w.cfg.Timeout <= 0 {
+		w.cfg.Timeout = defaultHTTPTimeout
+	}
+	switch {
+	case w.cfg.MaxRetries < 0:
+		w.cfg.MaxRetries = 0
+	case w.cfg.MaxRetries == 0:
+		w.cfg.MaxRetries = defaultHTTPMaxRetries
+	}
+	w.client = &http.Client{Timeout: w.cfg.Timeout}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+
+	go w.run()
+	return w, nil
+}
+
+func (w *httpWriter) Write(p []byte) (int, error) {
+	line := bytes.Clone(p)
+
+	w.mutex.Lock()
+	if len(w.lines) >= w.cfg.MaxBuffer {
+		w.lines = w.lines[1:]
+		w.dropped++
+	}
+	w.lines = append(w.lines, line)
+	full := len(w.lines) >= w.cfg.BatchSize
+	w.mutex.Unlock()
+
+	if full {
+		select {
+		case w.flush <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+func (w *httpWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			w.drain()
+			return
+		case <-ticker.C:
+		case <-w.flush:
+		}
+		for w.send() {
+		}
+	}
+}
+
+// send отправляет одну пачку, возвращает true если в буфере остались записи
+func (w *httpWriter) send() bool {
+	batch, more := w.take()
+	if len(batch) == 0 {
+		return false
+	}
+
+	body := bytes.Join(batch, nil)
+	backoff := httpInitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := w.post(body)
+		if err == nil {
+			return more
+		}
+		if attempt >= w.cfg.MaxRetries {
+			w.drop(len(batch), err)
+			return more
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-w.stop:
+			// пачка вернется в буфер и будет отправлена при закрытии
+			w.requeue(batch)
+			return false
+		}
+		backoff = min(backoff*2, httpMaxBackoff)
+	}
+}
+
+// drain отправляет оставшиеся записи без повторов. После первой ошибки
+// коллектор считается недоступным и остаток отбрасывается
+func (w *httpWriter) drain() {
+	for {
+		batch, _ := w.take()
+		if len(batch) == 0 {
+			return
+		}
+		if err := w.post(bytes.Join(batch, nil)); err != nil {
+			w.mutex.Lock()
+			n := len(batch) + len(w.lines)
+			w.lines = nil
+			w.mutex.Unlock()
+			w.drop(n, err)
+			return
+		}
+	}
+}
+
+// take забирает из буфера следующую пачку
+func (w *httpWriter) take() (batch [][]byte, more bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	n := min(len(w.lines), w.cfg.BatchSize)
+	batch = w.lines[:n:n]
+	w.lines = w.lines[n:]
+	return batch, len(w.lines) > 0
+}
+
+func (w *httpWriter) requeue(batch [][]byte) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.lines = append(batch, w.lines...)
+}
+
+func (w *httpWriter) drop(n int, err error) {
+	w.mutex.Lock()
+	w.dropped += uint64(n)
+	w.mutex.Unlock()
+	fmt.Fprintf(os.Stderr, "logger: dropped %d records: %v\n", n, err)
+}
+
+func (w *httpWriter) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(w.ctx, w.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	for key, value := range w.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("log collector responded with %s", res.Status)
+	}
+	return nil
+}
+
+// Dropped количество потерянных записей
+func (w *httpWriter) Dropped() uint64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.dropped
+}
+
+// Close отправляет оставшиеся записи и останавливает фоновую отправку.
+// Отправка, не уложившаяся в httpCloseTimeout, прерывается
+func (w *httpWriter) Close() error {
+	w.once.Do(func() {
+		close(w.stop)
+		time.AfterFunc(httpCloseTimeout, w.cancel)
+	})
+	<-w.done
+	w.cancel()
+	return nil
+}
@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector is a log collector that records the received lines and answers
+// with the status returned by respond.
+type collector struct {
+	mutex    sync.Mutex
+	requests int
+	lines    []string
+	respond  func(r *http.Request) int
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mutex.Lock()
+	c.requests++
+	respond := c.respond
+	c.mutex.Unlock()
+
+	code := http.StatusOK
+	if respond != nil {
+		code = respond(r)
+	}
+	if code == http.StatusOK {
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			c.mutex.Lock()
+			c.lines = append(c.lines, scanner.Text())
+			c.mutex.Unlock()
+		}
+	}
+	w.WriteHeader(code)
+}
+
+func (c *collector) stats() (requests int, lines []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.requests, append([]string(nil), c.lines...)
+}
+
+func newTestHTTPWriter(t *testing.T, c *collector, cfg HTTPSinkConfig) *httpWriter {
+	t.Helper()
+
+	server := httptest.NewServer(c)
+	t.Cleanup(server.Close)
+
+	cfg.URL = server.URL
+	if cfg.FlushInterval == 0 {
+		// only a full batch or Close sends records
+		cfg.FlushInterval = time.Hour
+	}
+	w, err := newHTTPWriter(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+func writeLines(w *httpWriter, n int) {
+	for i := range n {
+		fmt.Fprintf(w, "{\"n\":%d}\n", i)
+	}
+}
+
+func TestHTTPWriterDeliversBatches(t *testing.T) {
+	c := &collector{respond: func(r *http.Request) int {
+		if r.Header.Get("Content-Type") != "application/x-ndjson" || r.Header.Get("Authorization") != "token" {
+			return http.StatusBadRequest
+		}
+		return http.StatusOK
+	}}
+	w := newTestHTTPWriter(t, c, HTTPSinkConfig{BatchSize: 2, Headers: map[string]string{"Authorization": "token"}})
+
+	writeLines(w, 5)
+	w.Close()
+
+	requests, lines := c.stats()
+	if requests != 3 || len(lines) != 5 {
+		t.Fatalf("collector got %d lines in %d requests, want 5 in 3", len(lines), requests)
+	}
+	if lines[0] != `{"n":0}` || lines[4] != `{"n":4}` {
+		t.Fatalf("lines = %q, want them in write order", lines)
+	}
+	if dropped := w.Dropped(); dropped != 0 {
+		t.Fatalf("dropped = %d, want 0", dropped)
+	}
+}
+
+func TestHTTPWriterCloseDropsRemainderAfterFailure(t *testing.T) {
+	// an unresponsive collector: the requests run into the timeout
+	release := make(chan struct{})
+	c := &collector{respond: func(*http.Request) int {
+		<-release
+		return http.StatusServiceUnavailable
+	}}
+	w := newTestHTTPWriter(t, c, HTTPSinkConfig{BatchSize: 1, MaxBuffer: 10, Timeout: 50 * time.Millisecond})
+	t.Cleanup(func() { close(release) })
+
+	// the first batch starts sending at once and holds the rest in the buffer
+	writeLines(w, 5)
+	start := time.Now()
+	w.Close()
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Close took %v", elapsed)
+	}
+	if dropped := w.Dropped(); dropped != 5 {
+		t.Fatalf("dropped = %d, want 5", dropped)
+	}
+}
+
+func TestHTTPWriterRetries(t *testing.T) {
+	tests := []struct {
+		maxRetries   int
+		wantRequests int
+	}{
+		{maxRetries: -1, wantRequests: 1},
+		{maxRetries: 2, wantRequests: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.maxRetries), func(t *testing.T) {
+			c := &collector{respond: func(*http.Request) int { return http.StatusInternalServerError }}
+			w := newTestHTTPWriter(t, c, HTTPSinkConfig{BatchSize: 1, MaxRetries: tt.maxRetries})
+
+			writeLines(w, 1)
+			deadline := time.Now().Add(5 * time.Second)
+			for w.Dropped() == 0 {
+				if time.Now().After(deadline) {
+					t.Fatal("the batch was not dropped")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			if requests, _ := c.stats(); requests != tt.wantRequests {
+				t.Fatalf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+)
+
+// RootComponent имя уровня по умолчанию в реестре
+const RootComponent = "root"
+
+// levelAll пропускает все записи, фильтрацию выполняет реестр уровней
+const levelAll = slog.Level(math.MinInt)
+
+// ParseLevel разбор уровня из конфигурации
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q", level)
+	}
+}
+
+// LevelName имя уровня в формате конфигурации
+func LevelName(level slog.Level) string {
+	return strings.ToLower(level.String())
+}
+
+type componentLevel struct {
+	level    slog.LevelVar
+	explicit bool
+}
+
+// LevelRegistry реестр уровней именованных компонентов, изменяемых во время работы.
+// Компоненты без явно заданного уровня следуют уровню RootComponent.
+type LevelRegistry struct {
+	mutex      sync.RWMutex
+	root       slog.LevelVar
+	components map[string]*componentLevel
+}
+
+// NewLevelRegistry создание реестра с уровнем по умолчанию root
+func NewLevelRegistry(root slog.Level) *LevelRegistry {
+	r := &LevelRegistry{components: make(map[string]*componentLevel)}
+	r.root.Set(root)
+	return r
+}
+
+func (r *LevelRegistry) leveler(name string) slog.Leveler {
+	if name == RootComponent {
+		return &r.root
+	}
+
+	r.mutex.RLock()
+	c, ok := r.components[name]
+	r.mutex.RUnlock()
+	if ok {
+		return &c.level
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if c, ok = r.components[name]; !ok {
+		c = &componentLevel{}
+		c.level.Set(r.root.Level())
+		r.components[name] = c
+	}
+	return &c.level
+}
+
+// SetLevel задает уровень компонента, для RootComponent - уровень по умолчанию
+func (r *LevelRegistry) SetLevel(name string, level slog.Level) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if name == RootComponent {
+		r.root.Set(level)
+		for _, c := range r.components {
+			if !c.explicit {
+				c.level.Set(level)
+			}
+		}
+		return
+	}
+
+	c, ok := r.components[name]
+	if !ok {
+		c = &componentLevel{}
+		r.components[name] = c
+	}
+	c.explicit = true
+	c.level.Set(level)
+}
+
+// ResetLevel возвращает компонент к уровню по умолчанию
+func (r *LevelRegistry) ResetLevel(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if c, ok := r.components[name]; ok {
+		c.explicit = false
+		c.level.Set(r.root.Level())
+	}
+}
+
+// Levels текущие уровни всех известных компонентов, включая RootComponent
+func (r *LevelRegistry) Levels() map[string]slog.Level {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	levels := make(map[string]slog.Level, len(r.components)+1)
+	levels[RootComponent] = r.root.Level()
+	for name, c := range r.components {
+		levels[name] = c.level.Level()
+	}
+	return levels
+}
+
+// levelHandler отбрасывает записи ниже уровня компонента
+type levelHandler struct {
+	level   slog.Leveler
+	handler slog.Handler
+}
+
+func newLevelHandler(level slog.Leveler, handler slog.Handler) *levelHandler {
+	return &levelHandler{level: level, handler: handler}
+}
+
+func (lh *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= lh.level.Level() && lh.handler.Enabled(ctx, level)
+}
+
+func (lh *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return lh.handler.Handle(ctx, r)
+}
+
+func (lh *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{level: lh.level, handler: lh.handler.WithAttrs(attrs)}
+}
+
+func (lh *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{level: lh.level, handler: lh.handler.WithGroup(name)}
+}
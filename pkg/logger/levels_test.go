@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func assertLevel(t *testing.T, r *LevelRegistry, name string, want slog.Level) {
+	t.Helper()
+
+	if got := r.leveler(name).Level(); got != want {
+		t.Fatalf("level of %s = %v, want %v", name, got, want)
+	}
+}
+
+func TestLevelRegistryRootChange(t *testing.T) {
+	r := NewLevelRegistry(slog.LevelInfo)
+	// known before and after the root change
+	r.leveler("inherits")
+	r.SetLevel("explicit", slog.LevelError)
+
+	r.SetLevel(RootComponent, slog.LevelDebug)
+
+	assertLevel(t, r, "inherits", slog.LevelDebug)
+	assertLevel(t, r, "later", slog.LevelDebug)
+	assertLevel(t, r, "explicit", slog.LevelError)
+	assertLevel(t, r, RootComponent, slog.LevelDebug)
+
+	levels := r.Levels()
+	if levels[RootComponent] != slog.LevelDebug || levels["explicit"] != slog.LevelError || levels["inherits"] != slog.LevelDebug {
+		t.Fatalf("Levels = %v", levels)
+	}
+}
+
+func TestLevelRegistryResetLevel(t *testing.T) {
+	r := NewLevelRegistry(slog.LevelInfo)
+	r.SetLevel("grpc", slog.LevelError)
+
+	r.ResetLevel("grpc")
+	assertLevel(t, r, "grpc", slog.LevelInfo)
+
+	// the component follows the root again
+	r.SetLevel(RootComponent, slog.LevelWarn)
+	assertLevel(t, r, "grpc", slog.LevelWarn)
+
+	// resetting an unknown component is a no-op
+	r.ResetLevel("unknown")
+	if _, ok := r.Levels()["unknown"]; ok {
+		t.Fatal("ResetLevel registered an unknown component")
+	}
+}
+
+func TestLevelHandlerFollowsRegistry(t *testing.T) {
+	r := NewLevelRegistry(slog.LevelInfo)
+	recorder := &recordingHandler{}
+	log := slog.New(newLevelHandler(r.leveler("grpc"), recorder))
+
+	log.Debug("hidden")
+	r.SetLevel("grpc", slog.LevelDebug)
+	log.Debug("shown")
+
+	if passed, _ := recorder.split("hidden"); passed != 0 {
+		t.Fatal("debug record passed at level info")
+	}
+	if passed, _ := recorder.split("shown"); passed != 1 {
+		t.Fatal("debug record dropped after the level change")
+	}
+}
+
+func TestInitDefaultClosesPreviousPipeline(t *testing.T) {
+	prevLogger := slog.Default()
+	defaultMutex.RLock()
+	prevPipeline := defaultPipeline
+	defaultMutex.RUnlock()
+	t.Cleanup(func() {
+		Close()
+		slog.SetDefault(prevLogger)
+		defaultMutex.Lock()
+		defaultPipeline = prevPipeline
+		defaultMutex.Unlock()
+	})
+
+	dir := t.TempDir()
+	first := &Config{SinkConfig: SinkConfig{Path: filepath.Join(dir, "first.log")}}
+	if err := InitDefault(first); err != nil {
+		t.Fatal(err)
+	}
+	defaultMutex.RLock()
+	old := defaultPipeline.closers[0].(*rotatingFile)
+	defaultMutex.RUnlock()
+
+	second := &Config{SinkConfig: SinkConfig{Path: filepath.Join(dir, "second.log")}}
+	if err := InitDefault(second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.Write([]byte("late\n")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("write to the previous file = %v, want os.ErrClosed", err)
+	}
+}
@@ -2,12 +2,15 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"runtime"
+	"slices"
 	"sync"
+	"time"
 )
 
 type ctxKey string
@@ -16,8 +19,8 @@ const (
 	slogFields ctxKey = "slog_fields"
 )
 
-const callerDepth = 9
-
+// LoggerInterface структурный логгер. Методы без суффикса принимают пары
+// ключ/значение или slog.Attr, методы с суффиксом f - формат в стиле fmt.Printf
 type LoggerInterface interface {
 	Debug(msg string, args ...any)
 	DebugContext(ctx context.Context, msg string, args ...any)
@@ -35,6 +38,11 @@ type LoggerInterface interface {
 	WithGroup(name string) LoggerInterface
 
 	Log(ctx context.Context, level slog.Level, msg string, args ...any)
+
+	Debugf(format string, args ...any)
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+	Errorf(format string, args ...any)
 }
 
 type Logger struct {
@@ -47,18 +55,90 @@ type contextHandler struct {
 
 var _ LoggerInterface = (*Logger)(nil)
 
-// InitDefault инициализирует глобальный логгер
+// pipeline общие sink-и и реестр уровней, из которых собираются логгеры компонентов
+type pipeline struct {
+	sinks   slog.Handler
+	levels  *LevelRegistry
+	ring    *RingBuffer
+	closers []io.Closer
+}
+
+var (
+	defaultMutex    sync.RWMutex
+	defaultPipeline *pipeline
+)
+
+// InitDefault инициализирует глобальный логгер. Файлы и соединения предыдущего
+// глобального логгера закрываются после замены
 func InitDefault(cfg *Config) error {
-	logHandler, err := newHandler(cfg)
+	p, err := newPipeline(cfg)
 	if err != nil {
 		return err
 	}
 
-	l := slog.New(logHandler)
+	l := slog.New(p.handler(RootComponent))
 	slog.SetDefault(l)
+
+	defaultMutex.Lock()
+	prev := defaultPipeline
+	defaultPipeline = p
+	defaultMutex.Unlock()
+
+	if prev != nil {
+		if err := closeAll(prev.closers); err != nil {
+			l.Warn("failed to close the previous log outputs", "error", err)
+		}
+	}
 	return nil
 }
 
+// Component получение логгера именованного компонента с собственным уровнем
+func Component(name string) LoggerInterface {
+	defaultMutex.RLock()
+	p := defaultPipeline
+	defaultMutex.RUnlock()
+
+	if p == nil {
+		return Default().With("component", name)
+	}
+	return &Logger{log: slog.New(p.handler(name)).With("component", name)}
+}
+
+// Close закрывает файлы и соединения глобального логгера и дожидается отправки
+// буферизованных записей. Вызывается при завершении процесса
+func Close() error {
+	defaultMutex.RLock()
+	p := defaultPipeline
+	defaultMutex.RUnlock()
+
+	if p == nil {
+		return nil
+	}
+	return closeAll(p.closers)
+}
+
+// Ring кольцевой буфер последних записей глобального логгера, nil если выключен
+func Ring() *RingBuffer {
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+
+	if defaultPipeline == nil {
+		return nil
+	}
+	return defaultPipeline.ring
+}
+
+// Levels реестр уровней глобального логгера, nil до вызова InitDefault
+func Levels() *LevelRegistry {
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+
+	if defaultPipeline == nil {
+		return nil
+	}
+	return defaultPipeline.levels
+}
+
 // Default получение логгера по умолчанию
 func Default() LoggerInterface {
 	return &Logger{log: slog.Default()}
@@ -66,43 +146,43 @@ func Default() LoggerInterface {
 
 // New получение нового логгера
 func New(cfg *Config) (LoggerInterface, error) {
-	logHandler, err := newHandler(cfg)
+	p, err := newPipeline(cfg)
 	if err != nil {
 		return nil, err
 	}
-	return &Logger{log: slog.New(logHandler)}, nil
+	return &Logger{log: slog.New(p.handler(RootComponent))}, nil
 }
 
 func (l *Logger) Debug(msg string, args ...any) {
-	l.log.Debug(msg, args...)
+	l.emit(context.Background(), slog.LevelDebug, msg, args...)
 }
 
 func (l *Logger) DebugContext(ctx context.Context, msg string, args ...any) {
-	l.log.DebugContext(ctx, msg, args...)
+	l.emit(ctx, slog.LevelDebug, msg, args...)
 }
 
 func (l *Logger) Info(msg string, args ...any) {
-	l.log.Info(msg, args...)
+	l.emit(context.Background(), slog.LevelInfo, msg, args...)
 }
 
 func (l *Logger) InfoContext(ctx context.Context, msg string, args ...any) {
-	l.log.InfoContext(ctx, msg, args...)
+	l.emit(ctx, slog.LevelInfo, msg, args...)
 }
 
 func (l *Logger) Warn(msg string, args ...any) {
-	l.log.Warn(msg, args...)
+	l.emit(context.Background(), slog.LevelWarn, msg, args...)
 }
 
 func (l *Logger) WarnContext(ctx context.Context, msg string, args ...any) {
-	l.log.WarnContext(ctx, msg, args...)
+	l.emit(ctx, slog.LevelWarn, msg, args...)
 }
 
 func (l *Logger) Error(msg string, args ...any) {
-	l.log.Error(msg, args...)
+	l.emit(context.Background(), slog.LevelError, msg, args...)
 }
 
 func (l *Logger) ErrorContext(ctx context.Context, msg string, args ...any) {
-	l.log.ErrorContext(ctx, msg, args...)
+	l.emit(ctx, slog.LevelError, msg, args...)
 }
 
 func (l *Logger) With(args ...any) LoggerInterface {
@@ -114,7 +194,52 @@ func (l *Logger) WithGroup(name string) LoggerInterface {
 }
 
 func (l *Logger) Log(ctx context.Context, level slog.Level, msg string, args ...any) {
-	l.log.Log(ctx, level, msg, args...)
+	l.emit(ctx, level, msg, args...)
+}
+
+func (l *Logger) Debugf(format string, args ...any) {
+	l.emitf(slog.LevelDebug, format, args...)
+}
+
+func (l *Logger) Infof(format string, args ...any) {
+	l.emitf(slog.LevelInfo, format, args...)
+}
+
+func (l *Logger) Warnf(format string, args ...any) {
+	l.emitf(slog.LevelWarn, format, args...)
+}
+
+func (l *Logger) Errorf(format string, args ...any) {
+	l.emitf(slog.LevelError, format, args...)
+}
+
+func (l *Logger) emit(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !l.log.Enabled(ctx, level) {
+		return
+	}
+	l.handle(ctx, level, msg, args)
+}
+
+// emitf форматирует сообщение только если уровень включен
+func (l *Logger) emitf(level slog.Level, format string, args ...any) {
+	ctx := context.Background()
+	if !l.log.Enabled(ctx, level) {
+		return
+	}
+	l.handle(ctx, level, fmt.Sprintf(format, args...), nil)
+}
+
+// handle формирует запись с адресом вызывающего кода, чтобы source указывал
+// на место вызова, а не на обертку Logger
+func (l *Logger) handle(ctx context.Context, level slog.Level, msg string, args []any) {
+	var pcs [1]uintptr
+	runtime.Callers(4, pcs[:]) // runtime.Callers, handle, emit или emitf, метод Logger
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = l.log.Handler().Handle(ctx, r)
 }
 
 func newContextHandler(handler slog.Handler) *contextHandler {
@@ -126,13 +251,8 @@ func (ch *contextHandler) Enabled(ctx context.Context, rec slog.Level) bool {
 }
 
 func (ch *contextHandler) Handle(ctx context.Context, r slog.Record) error {
-	if attrs, ok := ctx.Value(slogFields).(*sync.Map); ok {
-		attrs.Range(func(key, val any) bool {
-			if keyString, ok := key.(string); ok {
-				r.AddAttrs(slog.Any(keyString, val))
-			}
-			return true
-		})
+	if attrs, ok := ctx.Value(slogFields).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
 	}
 	return ch.handler.Handle(ctx, r)
 }
@@ -145,67 +265,208 @@ func (ch *contextHandler) WithGroup(name string) slog.Handler {
 	return &contextHandler{handler: ch.handler.WithGroup(name)}
 }
 
-// AppendCtx добавление пары ключ/значение в контекст
+// AppendCtx добавление пары ключ/значение в контекст.
+// Значение может быть slog.Value, в том числе slog.GroupValue
 func AppendCtx(parent context.Context, key string, val any) context.Context {
+	return AppendCtxAttrs(parent, slog.Any(key, val))
+}
+
+// AppendCtxAttrs добавление атрибутов в контекст. Родительский контекст не
+// изменяется: дочерний получает копию списка полей. Порядок полей сохраняется,
+// повторный ключ заменяет значение на прежней позиции
+func AppendCtxAttrs(parent context.Context, attrs ...slog.Attr) context.Context {
 	if parent == nil {
 		parent = context.Background()
 	}
 
-	if v, ok := parent.Value(slogFields).(*sync.Map); ok {
-		v.Store(key, val)
-		return context.WithValue(parent, slogFields, v)
+	prev, _ := parent.Value(slogFields).([]slog.Attr)
+	fields := make([]slog.Attr, len(prev), len(prev)+len(attrs))
+	copy(fields, prev)
+	for _, attr := range attrs {
+		i := slices.IndexFunc(fields, func(a slog.Attr) bool { return a.Key == attr.Key })
+		if i >= 0 {
+			fields[i] = attr
+		} else {
+			fields = append(fields, attr)
+		}
 	}
-	v := &sync.Map{}
-	v.Store(key, val)
-	return context.WithValue(parent, slogFields, v)
+	return context.WithValue(parent, slogFields, fields)
 }
 
-func replaceAttrFunc(groups []string, a slog.Attr) slog.Attr {
-	if a.Key == slog.SourceKey {
-		source := a.Value.Any().(*slog.Source)
-		pc := make([]uintptr, 1)
-		runtime.Callers(callerDepth, pc)
-		fs := runtime.CallersFrames([]uintptr{pc[0]})
-		f, _ := fs.Next()
+// FieldsFromContext поля, добавленные в контекст через AppendCtx, в порядке добавления
+func FieldsFromContext(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(slogFields).([]slog.Attr)
+	return slices.Clone(fields)
+}
 
-		source.File = f.File
-		source.Function = f.Function
-		source.Line = f.Line
+func newWriter(cfg *SinkConfig) (io.Writer, error) {
+	output := cfg.Output
+	if output == "" {
+		output = "stdout"
+		if cfg.Path != "" {
+			output = "file"
+		}
+	}
+
+	switch output {
+	case "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	case "file":
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("log path is required for file output")
+		}
+		return openRotatingFile(cfg.Path, cfg.Rotation)
+	case "syslog":
+		return newSyslogWriter(&cfg.Syslog)
+	case "http":
+		return newHTTPWriter(&cfg.HTTP)
+	default:
+		return nil, fmt.Errorf("unknown log output %q", cfg.Output)
 	}
-	return a
 }
 
-func newHandler(cfg *Config) (slog.Handler, error) {
-	var logWriter io.Writer
-	var logLevel slog.Level
+func newPipeline(cfg *Config) (*pipeline, error) {
+	rootLevel, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	levels := NewLevelRegistry(rootLevel)
+	for name, level := range cfg.Components {
+		componentLevel, err := ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", name, err)
+		}
+		levels.SetLevel(name, componentLevel)
+	}
 
-	if cfg.Path == "" {
-		logWriter = os.Stdout
-	} else {
-		logFile, err := os.OpenFile(cfg.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0755)
+	sinks := cfg.Sinks
+	if len(sinks) == 0 {
+		sink := cfg.SinkConfig
+		sink.Level = ""
+		sinks = []SinkConfig{sink}
+	}
+
+	var closers []io.Closer
+	handlers := make([]slog.Handler, 0, len(sinks))
+	for i := range sinks {
+		handler, closer, err := newSinkHandler(&sinks[i])
 		if err != nil {
-			return nil, fmt.Errorf("failed to open log file %s: %w", cfg.Path, err)
+			closeAll(closers)
+			return nil, fmt.Errorf("log sink #%d: %w", i, err)
+		}
+		handlers = append(handlers, handler)
+		if closer != nil {
+			closers = append(closers, closer)
 		}
-		logWriter = logFile
-	}
-
-	switch cfg.Level {
-	case "debug":
-		logLevel = slog.LevelDebug
-	case "info":
-		logLevel = slog.LevelInfo
-	case "warn":
-		logLevel = slog.LevelWarn
-	case "error":
-		logLevel = slog.LevelError
-	default:
-		logLevel = slog.LevelError
+	}
+
+	var ring *RingBuffer
+	if cfg.RingBuffer.Size > 0 {
+		var level slog.Leveler = levelAll
+		if cfg.RingBuffer.Level != "" {
+			if level, err = ParseLevel(cfg.RingBuffer.Level); err != nil {
+				closeAll(closers)
+				return nil, fmt.Errorf("ring buffer: %w", err)
+			}
+		}
+		ring = NewRingBuffer(cfg.RingBuffer.Size)
+		handlers = append(handlers, newRingHandler(ring, level))
+	}
+
+	sinkHandler := handlers[0]
+	if len(handlers) > 1 {
+		sinkHandler = newFanoutHandler(handlers...)
+	}
+	sampled, sampler, err := newSamplingHandler(&cfg.Sampling, newRedactHandler(NewRedactor(&cfg.Redact), sinkHandler))
+	if err != nil {
+		closeAll(closers)
+		return nil, err
+	}
+	if sampler != nil {
+		// последние сводки пишутся до закрытия sink-ов
+		closers = append([]io.Closer{sampler}, closers...)
+	}
+	return &pipeline{sinks: sampled, levels: levels, ring: ring, closers: closers}, nil
+}
+
+func closeAll(closers []io.Closer) error {
+	var errs []error
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *pipeline) handler(component string) slog.Handler {
+	return newContextHandler(newLevelHandler(p.levels.leveler(component), p.sinks))
+}
+
+// newSinkHandler создание обработчика sink-а и ресурса, который нужно закрыть
+func newSinkHandler(cfg *SinkConfig) (slog.Handler, io.Closer, error) {
+	// sink без собственного уровня пропускает все, уровень определяет реестр
+	var level slog.Leveler = levelAll
+	if cfg.Level != "" {
+		sinkLevel, err := ParseLevel(cfg.Level)
+		if err != nil {
+			return nil, nil, err
+		}
+		level = sinkLevel
+	}
+
+	logWriter, err := newWriter(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	closer, _ := logWriter.(io.Closer)
+	if logWriter == os.Stdout || logWriter == os.Stderr {
+		closer = nil
 	}
 
 	logOptions := &slog.HandlerOptions{
-		Level:       logLevel,
-		AddSource:   true,
-		ReplaceAttr: replaceAttrFunc,
+		Level:     level,
+		AddSource: true,
+	}
+
+	format := cfg.Format
+	if cfg.Output == "http" {
+		// коллектор принимает NDJSON
+		format = "json"
+	}
+	newHandler := func(w io.Writer) (slog.Handler, error) {
+		return newFormatHandler(format, cfg.Color, w, logOptions)
+	}
+
+	var handler slog.Handler
+	if w, ok := logWriter.(*syslogWriter); ok {
+		handler, err = newSyslogHandler(w, newHandler)
+	} else {
+		handler, err = newHandler(logWriter)
+	}
+	if err != nil {
+		if closer != nil {
+			closer.Close()
+		}
+		return nil, nil, err
+	}
+	return handler, closer, nil
+}
+
+func newFormatHandler(format, color string, w io.Writer, opts *slog.HandlerOptions) (slog.Handler, error) {
+	switch format {
+	case "text":
+		return slog.NewTextHandler(w, opts), nil
+	case "console":
+		return newConsoleHandler(w, color, opts), nil
+	case "json", "":
+		return slog.NewJSONHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
 	}
-	return newContextHandler(slog.NewJSONHandler(logWriter, logOptions)), nil
 }
@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+type countingStringer struct {
+	calls int
+}
+
+func (s *countingStringer) String() string {
+	s.calls++
+	return "value"
+}
+
+func newTestLogger(buf *bytes.Buffer) *Logger {
+	handler := slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo, AddSource: true})
+	return &Logger{log: slog.New(handler)}
+}
+
+func TestFormatOnlyEnabledLevels(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf)
+	arg := &countingStringer{}
+
+	l.Debugf("disabled %s", arg)
+	if arg.calls != 0 || buf.Len() != 0 {
+		t.Fatalf("disabled Debugf formatted %d times and wrote %q", arg.calls, buf.String())
+	}
+
+	l.Infof("enabled %s", arg)
+	if arg.calls != 1 {
+		t.Fatalf("Infof formatted %d times, want 1", arg.calls)
+	}
+}
+
+func TestSourceIsCaller(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *Logger)
+	}{
+		{name: "Info", log: func(l *Logger) { l.Info("message") }},
+		{name: "InfoContext", log: func(l *Logger) { l.InfoContext(context.Background(), "message") }},
+		{name: "Infof", log: func(l *Logger) { l.Infof("%s", "message") }},
+		{name: "Log", log: func(l *Logger) { l.Log(context.Background(), slog.LevelWarn, "message") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(newTestLogger(&buf))
+
+			var record struct {
+				Msg    string `json:"msg"`
+				Source struct {
+					File string `json:"file"`
+				} `json:"source"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("decode %q: %v", buf.String(), err)
+			}
+			if record.Msg != "message" {
+				t.Errorf("msg = %q, want %q", record.Msg, "message")
+			}
+			if file := filepath.Base(record.Source.File); file != "logger_test.go" {
+				t.Errorf("source file = %s, want logger_test.go", file)
+			}
+		})
+	}
+}
+
+func fieldKeys(ctx context.Context) []string {
+	var keys []string
+	for _, a := range FieldsFromContext(ctx) {
+		keys = append(keys, a.Key+"="+a.Value.String())
+	}
+	return keys
+}
+
+func TestAppendCtxAttrsDoesNotLeak(t *testing.T) {
+	parent := AppendCtx(context.Background(), "request_id", "r1")
+	child := AppendCtx(parent, "user", "alice")
+	sibling := AppendCtx(parent, "user", "bob")
+
+	if got := fieldKeys(parent); !slices.Equal(got, []string{"request_id=r1"}) {
+		t.Errorf("parent = %v", got)
+	}
+	if got := fieldKeys(child); !slices.Equal(got, []string{"request_id=r1", "user=alice"}) {
+		t.Errorf("child = %v", got)
+	}
+	if got := fieldKeys(sibling); !slices.Equal(got, []string{"request_id=r1", "user=bob"}) {
+		t.Errorf("sibling = %v", got)
+	}
+
+	// the returned slice is a copy
+	fields := FieldsFromContext(child)
+	fields[0] = slog.String("request_id", "changed")
+	if got := fieldKeys(child); got[0] != "request_id=r1" {
+		t.Errorf("FieldsFromContext exposed the context fields: %v", got)
+	}
+}
+
+func TestAppendCtxAttrsOrderAndReplace(t *testing.T) {
+	ctx := AppendCtxAttrs(context.Background(), slog.String("a", "1"), slog.String("b", "2"), slog.String("c", "3"))
+	ctx = AppendCtxAttrs(ctx, slog.String("b", "changed"), slog.String("d", "4"))
+
+	want := []string{"a=1", "b=changed", "c=3", "d=4"}
+	if got := fieldKeys(ctx); !slices.Equal(got, want) {
+		t.Fatalf("fields = %v, want %v", got, want)
+	}
+	if fields := FieldsFromContext(nil); fields != nil {
+		t.Fatalf("FieldsFromContext(nil) = %v", fields)
+	}
+}
+
+func TestAppendCtxGroup(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, nil)
+	l := &Logger{log: slog.New(newContextHandler(handler))}
+
+	ctx := AppendCtx(context.Background(), "http", slog.GroupValue(slog.String("method", "GET"), slog.Int("status", 200)))
+	l.InfoContext(ctx, "handled")
+
+	var record struct {
+		HTTP struct {
+			Method string `json:"method"`
+			Status int    `json:"status"`
+		} `json:"http"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode %q: %v", buf.String(), err)
+	}
+	if record.HTTP.Method != "GET" || record.HTTP.Status != 200 {
+		t.Fatalf("record = %q, want the http group", buf.String())
+	}
+}
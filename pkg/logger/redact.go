@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"strings"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/options"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DefaultMask значение, которым заменяются скрытые данные
+const DefaultMask = "[REDACTED]"
+
+// DefaultRedactKeys шаблоны ключей, которые скрываются всегда
+var DefaultRedactKeys = []string{
+	"*password*",
+	"*secret*",
+	"*token*",
+	"*api_key*",
+	"*apikey*",
+	"authorization",
+	"cookie",
+}
+
+// Redactor скрывает значения атрибутов по шаблону ключа и поля protobuf
+// сообщений, помеченные опцией (options.sensitive)
+type Redactor struct {
+	patterns []string
+	mask     string
+}
+
+// NewRedactor создание Redactor из конфигурации, nil - только шаблоны по умолчанию
+func NewRedactor(cfg *RedactConfig) *Redactor {
+	r := &Redactor{mask: DefaultMask}
+	if cfg != nil && cfg.Mask != "" {
+		r.mask = cfg.Mask
+	}
+	if cfg == nil || !cfg.DisableDefaults {
+		r.patterns = append(r.patterns, DefaultRedactKeys...)
+	}
+	if cfg != nil {
+		for _, key := range cfg.Keys {
+			r.patterns = append(r.patterns, strings.ToLower(key))
+		}
+	}
+	return r
+}
+
+func (r *Redactor) matches(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range r.patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Attr скрытие значения атрибута, группы обрабатываются рекурсивно
+func (r *Redactor) Attr(a slog.Attr) slog.Attr {
+	if r.matches(a.Key) {
+		return slog.String(a.Key, r.mask)
+	}
+
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = r.Attr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindAny:
+		if msg, ok := value.Any().(proto.Message); ok {
+			return slog.Any(a.Key, r.Proto(msg))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: value}
+}
+
+// Proto копия сообщения со скрытыми чувствительными полями
+func (r *Redactor) Proto(msg proto.Message) proto.Message {
+	if msg == nil {
+		return nil
+	}
+	clone := proto.Clone(msg)
+	redactMessage(clone.ProtoReflect(), r.mask)
+	return clone
+}
+
+func redactMessage(m protoreflect.Message, mask string) {
+	var sensitive []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case isSensitive(fd):
+			sensitive = append(sensitive, fd)
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					redactMessage(mv.Message(), mask)
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					redactMessage(list.Get(i).Message(), mask)
+				}
+			}
+		case fd.Message() != nil:
+			redactMessage(v.Message(), mask)
+		}
+		return true
+	})
+
+	for _, fd := range sensitive {
+		if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+			m.Set(fd, protoreflect.ValueOfString(mask))
+		} else {
+			m.Clear(fd)
+		}
+	}
+}
+
+func isSensitive(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return false
+	}
+	sensitive, _ := proto.GetExtension(opts, options.E_Sensitive).(bool)
+	return sensitive
+}
+
+// redactHandler скрывает данные в записях перед передачей в sink-и
+type redactHandler struct {
+	redactor *Redactor
+	handler  slog.Handler
+}
+
+func newRedactHandler(redactor *Redactor, handler slog.Handler) *redactHandler {
+	return &redactHandler{redactor: redactor, handler: handler}
+}
+
+func (rh *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return rh.handler.Enabled(ctx, level)
+}
+
+func (rh *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(rh.redactor.Attr(a))
+		return true
+	})
+	return rh.handler.Handle(ctx, redacted)
+}
+
+func (rh *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = rh.redactor.Attr(a)
+	}
+	return &redactHandler{redactor: rh.redactor, handler: rh.handler.WithAttrs(redacted)}
+}
+
+func (rh *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{redactor: rh.redactor, handler: rh.handler.WithGroup(name)}
+}
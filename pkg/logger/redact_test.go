@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/options"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestRedactorAttr(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *RedactConfig
+		attr slog.Attr
+		want string
+	}{
+		{name: "default key", attr: slog.String("db_password", "x"), want: DefaultMask},
+		{name: "case insensitive", attr: slog.String("Authorization", "x"), want: DefaultMask},
+		{name: "other key", attr: slog.String("user", "x"), want: "x"},
+		{name: "configured key", cfg: &RedactConfig{Keys: []string{"User*"}}, attr: slog.String("user_id", "x"), want: DefaultMask},
+		{name: "configured mask", cfg: &RedactConfig{Mask: "***"}, attr: slog.String("token", "x"), want: "***"},
+		{name: "defaults disabled", cfg: &RedactConfig{DisableDefaults: true}, attr: slog.String("token", "x"), want: "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewRedactor(tt.cfg).Attr(tt.attr)
+			if got.Value.String() != tt.want {
+				t.Errorf("Attr(%s) = %q, want %q", tt.attr.Key, got.Value.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorGroup(t *testing.T) {
+	got := NewRedactor(nil).Attr(slog.Group("request", slog.String("token", "x"), slog.String("user", "u")))
+
+	values := map[string]string{}
+	for _, a := range got.Value.Group() {
+		values[a.Key] = a.Value.String()
+	}
+	if values["token"] != DefaultMask || values["user"] != "u" {
+		t.Fatalf("group = %v", values)
+	}
+}
+
+// credentialsMessage builds a message {user, secret} whose secret field is
+// marked with (options.sensitive).
+func credentialsMessage(t *testing.T) *dynamicpb.Message {
+	t.Helper()
+
+	sensitive := &descriptorpb.FieldOptions{}
+	proto.SetExtension(sensitive, options.E_Sensitive, true)
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("redact_test.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Credentials"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("user"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), JsonName: proto.String("user")},
+				{Name: proto.String("secret"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), JsonName: proto.String("secret"), Options: sensitive},
+			},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := dynamicpb.NewMessage(file.Messages().ByName("Credentials"))
+	msg.Set(msg.Descriptor().Fields().ByName("user"), protoreflect.ValueOfString("alice"))
+	msg.Set(msg.Descriptor().Fields().ByName("secret"), protoreflect.ValueOfString("hunter2"))
+	return msg
+}
+
+// captureHandler keeps the attributes of the last record.
+type captureHandler struct {
+	attrs map[string]slog.Value
+}
+
+func (h *captureHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *captureHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	h.attrs = map[string]slog.Value{}
+	r.Attrs(func(a slog.Attr) bool {
+		h.attrs[a.Key] = a.Value
+		return true
+	})
+	return nil
+}
+
+func TestRedactHandlerMasksSensitiveProtoFields(t *testing.T) {
+	capture := &captureHandler{}
+	handler := newRedactHandler(NewRedactor(&RedactConfig{Mask: "***"}), capture)
+	msg := credentialsMessage(t)
+
+	// payloads are passed as key/value pairs, as the gRPC logging interceptor does
+	slog.New(handler).Log(context.Background(), slog.LevelInfo, "call", "grpc.request.content", msg)
+
+	logged, ok := capture.attrs["grpc.request.content"].Any().(proto.Message)
+	if !ok {
+		t.Fatalf("logged %v, want a proto message", capture.attrs["grpc.request.content"])
+	}
+	fields := logged.ProtoReflect().Descriptor().Fields()
+	user := logged.ProtoReflect().Get(fields.ByName("user")).String()
+	secret := logged.ProtoReflect().Get(fields.ByName("secret")).String()
+	if user != "alice" || secret != "***" {
+		t.Fatalf("logged user %q and secret %q, want alice and ***", user, secret)
+	}
+
+	// the logged message is a copy
+	if secret := msg.Get(fields.ByName("secret")).String(); secret != "hunter2" {
+		t.Fatalf("original message changed to %q", secret)
+	}
+}
@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const defaultRingSubscriberBuffer = 256
+
+// Field атрибут записи в виде строки, ключи вложенных групп разделены точкой
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Entry запись в кольцевом буфере
+type Entry struct {
+	Seq     uint64
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Fields  []Field
+}
+
+// Field значение атрибута по ключу
+func (e *Entry) Field(key string) (string, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// RingBuffer хранит последние записи в памяти и раздает новые подписчикам
+type RingBuffer struct {
+	mutex   sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+	seq     uint64
+	subs    map[chan Entry]struct{}
+}
+
+// NewRingBuffer создание буфера на capacity записей
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{
+		entries: make([]Entry, capacity),
+		subs:    make(map[chan Entry]struct{}),
+	}
+}
+
+func (b *RingBuffer) add(e Entry) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.seq++
+	e.Seq = b.seq
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	// медленный подписчик теряет записи, но не блокирует логирование
+	for sub := range b.subs {
+		select {
+		case sub <- e:
+		default:
+		}
+	}
+}
+
+// Snapshot записи в буфере от старых к новым
+func (b *RingBuffer) Snapshot() []Entry {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.full {
+		return append([]Entry(nil), b.entries[:b.next]...)
+	}
+	snapshot := make([]Entry, 0, len(b.entries))
+	snapshot = append(snapshot, b.entries[b.next:]...)
+	return append(snapshot, b.entries[:b.next]...)
+}
+
+// Subscribe подписка на новые записи, функция отмены закрывает канал
+func (b *RingBuffer) Subscribe() (<-chan Entry, func()) {
+	sub := make(chan Entry, defaultRingSubscriberBuffer)
+
+	b.mutex.Lock()
+	b.subs[sub] = struct{}{}
+	b.mutex.Unlock()
+
+	var once sync.Once
+	return sub, func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			delete(b.subs, sub)
+			close(sub)
+			b.mutex.Unlock()
+		})
+	}
+}
+
+// ringHandler обработчик slog, записывающий в RingBuffer
+type ringHandler struct {
+	buffer *RingBuffer
+	level  slog.Leveler
+	prefix string
+	fields []Field
+}
+
+func newRingHandler(buffer *RingBuffer, level slog.Leveler) *ringHandler {
+	return &ringHandler{buffer: buffer, level: level}
+}
+
+func (rh *ringHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= rh.level.Level()
+}
+
+func (rh *ringHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := append([]Field(nil), rh.fields...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendField(fields, rh.prefix, a)
+		return true
+	})
+	rh.buffer.add(Entry{Time: r.Time, Level: r.Level, Message: r.Message, Fields: fields})
+	return nil
+}
+
+func (rh *ringHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *rh
+	clone.fields = append([]Field(nil), rh.fields...)
+	for _, a := range attrs {
+		clone.fields = appendField(clone.fields, rh.prefix, a)
+	}
+	return &clone
+}
+
+func (rh *ringHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return rh
+	}
+	clone := *rh
+	clone.prefix = rh.prefix + name + "."
+	return &clone
+}
+
+func appendField(fields []Field, prefix string, a slog.Attr) []Field {
+	value := a.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range value.Group() {
+			fields = appendField(fields, groupPrefix, ga)
+		}
+		return fields
+	}
+	if a.Key == "" {
+		return fields
+	}
+
+	var s string
+	switch value.Kind() {
+	case slog.KindString:
+		s = value.String()
+	case slog.KindTime:
+		s = value.Time().Format(time.RFC3339Nano)
+	default:
+		s = fmt.Sprint(value.Any())
+	}
+	return append(fields, Field{Key: prefix + a.Key, Value: s})
+}
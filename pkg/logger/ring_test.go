@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func newTestRing(capacity int, level slog.Level) (*RingBuffer, *slog.Logger) {
+	b := NewRingBuffer(capacity)
+	return b, slog.New(newRingHandler(b, level))
+}
+
+func TestRingBufferSnapshotAfterWraparound(t *testing.T) {
+	b, log := newTestRing(3, levelAll)
+	if len(b.Snapshot()) != 0 {
+		t.Fatal("snapshot of an empty buffer is not empty")
+	}
+	for i := range 5 {
+		log.Info(fmt.Sprint(i))
+	}
+
+	snapshot := b.Snapshot()
+	if len(snapshot) != 3 {
+		t.Fatalf("snapshot has %d entries, want 3", len(snapshot))
+	}
+	for i, e := range snapshot {
+		if want := fmt.Sprint(i + 2); e.Message != want || e.Seq != uint64(i+3) {
+			t.Errorf("entry %d = %s seq %d, want %s seq %d", i, e.Message, e.Seq, want, i+3)
+		}
+	}
+}
+
+func TestRingHandlerFields(t *testing.T) {
+	b, log := newTestRing(10, slog.LevelInfo)
+
+	log.Debug("hidden")
+	log.With("service", "api").WithGroup("req").Info("handled", "id", 7, slog.Group("user", "name", "alice"))
+
+	snapshot := b.Snapshot()
+	if len(snapshot) != 1 {
+		t.Fatalf("snapshot = %+v, want only the info entry", snapshot)
+	}
+	e := snapshot[0]
+	for key, want := range map[string]string{"service": "api", "req.id": "7", "req.user.name": "alice"} {
+		if got, ok := e.Field(key); !ok || got != want {
+			t.Errorf("field %s = %q, want %q", key, got, want)
+		}
+	}
+	if _, ok := e.Field("id"); ok {
+		t.Error("field id without the group prefix")
+	}
+}
+
+func TestRingBufferSlowSubscriber(t *testing.T) {
+	b, log := newTestRing(10, levelAll)
+	entries, cancel := b.Subscribe()
+
+	done := make(chan struct{})
+	go func() {
+		for range defaultRingSubscriberBuffer + 10 {
+			log.Info("flood")
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("logging blocked on a subscriber that does not read")
+	}
+
+	if n := len(entries); n != defaultRingSubscriberBuffer {
+		t.Fatalf("subscriber got %d entries, want %d", n, defaultRingSubscriberBuffer)
+	}
+	if e := <-entries; e.Seq != 1 {
+		t.Fatalf("first entry seq = %d, want 1", e.Seq)
+	}
+
+	cancel()
+	cancel()
+	for range entries {
+	}
+	// the cancelled subscriber no longer receives entries
+	log.Info("after cancel")
+}
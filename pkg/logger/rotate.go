@@ -0,0 +1,333 @@
+package logger
+
+import (
+	"cmp"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+	megabyte         = 1024 * 1024
+	rotateRetryDelay = time.Minute
+)
+
+// rotatingFile файл лога с ротацией по размеру и возрасту
+type rotatingFile struct {
+	path string
+	cfg  RotationConfig
+
+	mutex    sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	retryAt  time.Time
+	closed   bool
+
+	cleanupMutex sync.Mutex
+
+	signals    chan os.Signal
+	stopSignal chan struct{}
+	signalDone chan struct{}
+	stopOnce   sync.Once
+}
+
+func openRotatingFile(path string, cfg RotationConfig) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, cfg: cfg}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	if cfg.ReopenOnSignal {
+		f.signals = make(chan os.Signal, 1)
+		f.stopSignal = make(chan struct{})
+		f.signalDone = make(chan struct{})
+		signal.Notify(f.signals, syscall.SIGHUP)
+		go f.reopenOnSignal()
+	}
+	return f, nil
+}
+
+// reopenOnSignal переоткрывает файл по SIGHUP до вызова Close
+func (f *rotatingFile) reopenOnSignal() {
+	defer close(f.signalDone)
+
+	for {
+		select {
+		case <-f.signals:
+			if err := f.Reopen(); err != nil {
+				fmt.Fprintf(os.Stderr, "logger: %v\n", err)
+			}
+		case <-f.stopSignal:
+			return
+		}
+	}
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %w", f.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file %s: %w", f.path, err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+	if f.size > 0 {
+		f.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+// Write пишет запись, при необходимости ротируя файл. Если ротация не удалась,
+// запись попадает в прежний файл, а ошибка выводится в stderr
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if f.file != nil && f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: %v\n", err)
+		}
+	}
+	// файл мог остаться закрытым после неудачного переоткрытия
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Reopen закрывает и заново открывает файл, например после переименования внешним logrotate
+func (f *rotatingFile) Reopen() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.closed {
+		return os.ErrClosed
+	}
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: failed to close log file %s: %v\n", f.path, err)
+		}
+		f.file = nil
+	}
+	return f.open()
+}
+
+func (f *rotatingFile) Close() error {
+	if f.signals != nil {
+		f.stopOnce.Do(func() {
+			signal.Stop(f.signals)
+			close(f.stopSignal)
+		})
+		<-f.signalDone
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *rotatingFile) shouldRotate(next int64) bool {
+	if f.size == 0 || time.Now().Before(f.retryAt) {
+		return false
+	}
+	if f.cfg.MaxSize > 0 && f.size+next > int64(f.cfg.MaxSize)*megabyte {
+		return true
+	}
+	return f.cfg.MaxAge > 0 && time.Since(f.openedAt) >= f.cfg.MaxAge
+}
+
+// rotate переименовывает текущий файл в архив и открывает новый. При ошибке
+// переименования снова открывается прежний файл, чтобы запись продолжалась
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "logger: failed to close log file %s: %v\n", f.path, err)
+	}
+	f.file = nil
+
+	backup := f.backupName(time.Now())
+	renameErr := os.Rename(f.path, backup)
+	if renameErr != nil {
+		renameErr = fmt.Errorf("failed to rotate log file %s: %w", f.path, renameErr)
+	}
+	if err := f.open(); err != nil {
+		return errors.Join(renameErr, err)
+	}
+	if renameErr != nil {
+		// не повторять ротацию на каждой записи
+		f.retryAt = time.Now().Add(rotateRetryDelay)
+		return renameErr
+	}
+
+	go f.cleanup(backup)
+	return nil
+}
+
+// backupName имя архива со временем ротации; если файл с таким именем уже
+// есть, добавляется счетчик: app-<время>-1.log
+func (f *rotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext) + "-" + t.Format(backupTimeFormat)
+
+	name := base + ext
+	for i := 1; backupExists(name); i++ {
+		name = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	return name
+}
+
+func backupExists(name string) bool {
+	for _, path := range []string{name, name + compressSuffix} {
+		if _, err := os.Lstat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanup сжимает свежий архив и удаляет лишние старые файлы
+func (f *rotatingFile) cleanup(backup string) {
+	f.cleanupMutex.Lock()
+	defer f.cleanupMutex.Unlock()
+
+	if f.cfg.Compress {
+		if err := compressFile(backup); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: %v\n", err)
+		}
+	}
+	if f.cfg.MaxBackups > 0 {
+		if err := f.prune(); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: %v\n", err)
+		}
+	}
+}
+
+func (f *rotatingFile) prune() error {
+	dir := filepath.Dir(f.path)
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list log backups in %s: %w", dir, err)
+	}
+
+	type backup struct {
+		name  string
+		stamp string
+		n     int
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		rest = strings.TrimSuffix(strings.TrimSuffix(rest, compressSuffix), ext)
+		stamp, n, ok := parseBackupStamp(rest)
+		if !ok {
+			continue
+		}
+		backups = append(backups, backup{name: name, stamp: stamp, n: n})
+	}
+
+	// время в сортируемом формате, затем счетчик; новые в конце
+	slices.SortFunc(backups, func(a, b backup) int {
+		return cmp.Or(strings.Compare(a.stamp, b.stamp), cmp.Compare(a.n, b.n))
+	})
+	for len(backups) > f.cfg.MaxBackups {
+		if err := os.Remove(filepath.Join(dir, backups[0].name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove log backup %s: %w", backups[0].name, err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// parseBackupStamp разбирает "<время>" или "<время>-<счетчик>" из имени архива
+func parseBackupStamp(s string) (string, int, bool) {
+	stamp, n := s, 0
+	if len(s) > len(backupTimeFormat) {
+		counter, ok := strings.CutPrefix(s[len(backupTimeFormat):], "-")
+		parsed, err := strconv.Atoi(counter)
+		if !ok || err != nil || parsed < 1 {
+			return "", 0, false
+		}
+		stamp, n = s[:len(backupTimeFormat)], parsed
+	}
+	if _, err := time.Parse(backupTimeFormat, stamp); err != nil {
+		return "", 0, false
+	}
+	return stamp, n, true
+}
+
+// compressFile заменяет файл его gzip-копией. Файл, уже удаленный при
+// очистке старых архивов, пропускается
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open log backup %s: %w", path, err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create compressed log backup %s: %w", path, err)
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(path + compressSuffix)
+		return fmt.Errorf("failed to compress log backup %s: %w", path, err)
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + compressSuffix)
+		return fmt.Errorf("failed to compress log backup %s: %w", path, err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + compressSuffix)
+		return fmt.Errorf("failed to compress log backup %s: %w", path, err)
+	}
+	return os.Remove(path)
+}
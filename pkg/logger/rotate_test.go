@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	f, err := openRotatingFile(filepath.Join(dir, "app.log"), RotationConfig{MaxSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// every record but the first exceeds 1 MB together with the previous one;
+	// rotations within the same millisecond must not overwrite each other
+	record := bytes.Repeat([]byte("x"), 600*1024)
+	for range 3 {
+		if _, err := f.Write(record); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	names := listDir(t, dir)
+	if len(names) != 3 {
+		t.Fatalf("files = %v, want the log and 2 backups", names)
+	}
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() != int64(len(record)) {
+			t.Errorf("%s has %d bytes, want %d", name, info.Size(), len(record))
+		}
+	}
+}
+
+func TestRotatePrunesBackups(t *testing.T) {
+	dir := t.TempDir()
+	f, err := openRotatingFile(filepath.Join(dir, "app.log"), RotationConfig{MaxSize: 1, MaxBackups: 1, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	record := bytes.Repeat([]byte("x"), 600*1024)
+	for range 4 {
+		if _, err := f.Write(record); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	// сжатие и удаление выполняются в фоне
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		names := listDir(t, dir)
+		if len(names) == 2 && filepath.Ext(names[0]) == compressSuffix {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("files = %v, want the log and one compressed backup", names)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBackupName(t *testing.T) {
+	dir := t.TempDir()
+	f := &rotatingFile{path: filepath.Join(dir, "app.log")}
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	want := []string{
+		"app-2024-05-01T10-30-00.000.log",
+		"app-2024-05-01T10-30-00.000-1.log",
+		"app-2024-05-01T10-30-00.000-2.log",
+	}
+	for i, name := range want {
+		got := f.backupName(now)
+		if filepath.Base(got) != name {
+			t.Fatalf("backup %d = %s, want %s", i, filepath.Base(got), name)
+		}
+		// a compressed backup takes the name as well
+		if err := os.WriteFile(got+compressSuffix, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseBackupStamp(t *testing.T) {
+	tests := []struct {
+		in    string
+		stamp string
+		n     int
+		ok    bool
+	}{
+		{in: "2024-05-01T10-30-00.000", stamp: "2024-05-01T10-30-00.000", ok: true},
+		{in: "2024-05-01T10-30-00.000-12", stamp: "2024-05-01T10-30-00.000", n: 12, ok: true},
+		{in: "2024-05-01T10-30-00.000-0"},
+		{in: "2024-05-01T10-30-00.000-x"},
+		{in: "2024-05-01T10-30-00.000x1"},
+		{in: "current"},
+	}
+
+	for _, tt := range tests {
+		stamp, n, ok := parseBackupStamp(tt.in)
+		if stamp != tt.stamp || n != tt.n || ok != tt.ok {
+			t.Errorf("parseBackupStamp(%q) = %q, %d, %v, want %q, %d, %v", tt.in, stamp, n, ok, tt.stamp, tt.n, tt.ok)
+		}
+	}
+}
+
+func TestWriteRecoversAfterFailedReopen(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "app.log")
+	f, err := openRotatingFile(path, RotationConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Reopen(); err == nil {
+		t.Fatal("Reopen without the log directory succeeded")
+	}
+	if _, err := f.Write([]byte("lost\n")); err == nil {
+		t.Fatal("Write without the log directory succeeded")
+	}
+
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("kept\n")); err != nil {
+		t.Fatalf("Write after the directory is back: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "kept\n" {
+		t.Fatalf("log = %q, want %q", data, "kept\n")
+	}
+}
+
+func TestWriteAfterClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := openRotatingFile(path, RotationConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("late\n")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("Write after Close = %v, want os.ErrClosed", err)
+	}
+}
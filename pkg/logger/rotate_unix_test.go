@@ -0,0 +1,47 @@
+//go:build unix
+
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReopenOnSignal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := openRotatingFile(path, RotationConfig{ReopenOnSignal: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// внешний logrotate переименовывает файл и присылает SIGHUP
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("log file not reopened after SIGHUP")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// Close останавливает обработчик сигнала и может вызываться повторно
+	if err := f.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
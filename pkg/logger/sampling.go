@@ -0,0 +1,176 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const defaultSamplingInterval = time.Second
+
+type samplingKey struct {
+	level slog.Level
+	msg   string
+}
+
+type samplingCounter struct {
+	seen       uint64
+	suppressed uint64
+}
+
+// sampler общее состояние семплирования для всех производных обработчиков.
+// Интервалы отсчитывает таймер, который останавливается в Close
+type sampler struct {
+	interval time.Duration
+	levels   map[slog.Level]LevelSampling
+	base     slog.Handler
+
+	mutex       sync.Mutex
+	windowStart time.Time
+	counters    map[samplingKey]*samplingCounter
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// samplingHandler пропускает первые First одинаковых сообщений за интервал,
+// затем каждое Thereafter-е. Сводка о подавленных сообщениях пишется в конце
+// каждого интервала и при закрытии, даже если новых записей больше нет
+type samplingHandler struct {
+	sampler *sampler
+	handler slog.Handler
+}
+
+// newSamplingHandler возвращает handler без изменений, если семплирование
+// выключено; иначе закрытие sampler-а пишет последние сводки
+func newSamplingHandler(cfg *SamplingConfig, handler slog.Handler) (slog.Handler, io.Closer, error) {
+	if len(cfg.Levels) == 0 {
+		return handler, nil, nil
+	}
+
+	s := &sampler{
+		interval:    cfg.Interval,
+		levels:      make(map[slog.Level]LevelSampling, len(cfg.Levels)),
+		base:        handler,
+		windowStart: time.Now(),
+		counters:    make(map[samplingKey]*samplingCounter),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	if s.interval <= 0 {
+		s.interval = defaultSamplingInterval
+	}
+	for name, levelSampling := range cfg.Levels {
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.levels[level] = levelSampling
+	}
+	go s.run()
+	return &samplingHandler{sampler: s, handler: handler}, s, nil
+}
+
+func (sh *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return sh.handler.Enabled(ctx, level)
+}
+
+func (sh *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !sh.sampler.sample(r.Level, r.Message) {
+		return nil
+	}
+	return sh.handler.Handle(ctx, r)
+}
+
+func (sh *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{sampler: sh.sampler, handler: sh.handler.WithAttrs(attrs)}
+}
+
+func (sh *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{sampler: sh.sampler, handler: sh.handler.WithGroup(name)}
+}
+
+func (s *sampler) sample(level slog.Level, msg string) bool {
+	cfg, ok := s.levels[level]
+	if !ok {
+		return true
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := samplingKey{level: level, msg: msg}
+	counter, ok := s.counters[key]
+	if !ok {
+		counter = &samplingCounter{}
+		s.counters[key] = counter
+	}
+	counter.seen++
+
+	first := uint64(cfg.First)
+	pass := counter.seen <= first ||
+		(cfg.Thereafter > 0 && (counter.seen-first)%uint64(cfg.Thereafter) == 0)
+	if !pass {
+		counter.suppressed++
+	}
+	return pass
+}
+
+func (s *sampler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			s.flush(now)
+		case <-s.stop:
+			s.flush(time.Now())
+			return
+		}
+	}
+}
+
+// Close останавливает таймер и пишет сводки за незавершенный интервал
+func (s *sampler) Close() error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+	return nil
+}
+
+// flush пишет сводки за прошедший интервал и начинает новый
+func (s *sampler) flush(now time.Time) {
+	s.mutex.Lock()
+	summaries := s.rollover(now)
+	s.mutex.Unlock()
+
+	for _, summary := range summaries {
+		_ = s.base.Handle(context.Background(), summary)
+	}
+}
+
+// rollover начинает новый интервал и возвращает сводки за прошедший
+func (s *sampler) rollover(now time.Time) []slog.Record {
+	var summaries []slog.Record
+	for key, counter := range s.counters {
+		if counter.suppressed == 0 {
+			continue
+		}
+		summary := slog.NewRecord(now, key.level, "suppressed log messages", 0)
+		summary.AddAttrs(
+			slog.String("message", key.msg),
+			slog.Uint64("suppressed", counter.suppressed),
+			slog.String("interval", now.Sub(s.windowStart).Round(time.Millisecond).String()),
+		)
+		summaries = append(summaries, summary)
+	}
+	s.windowStart = now
+	clear(s.counters)
+	return summaries
+}
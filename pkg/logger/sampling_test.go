@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingHandler keeps every record, also those written by the sampler timer.
+type recordingHandler struct {
+	mutex   sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.records = append(h.records, r.Clone())
+	return nil
+}
+
+// split returns the number of passed records with message msg and the
+// suppressed counts of the summaries.
+func (h *recordingHandler) split(msg string) (passed int, suppressed []uint64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, r := range h.records {
+		switch r.Message {
+		case msg:
+			passed++
+		case "suppressed log messages":
+			r.Attrs(func(a slog.Attr) bool {
+				if a.Key == "suppressed" {
+					suppressed = append(suppressed, a.Value.Uint64())
+				}
+				return true
+			})
+		}
+	}
+	return passed, suppressed
+}
+
+func newTestSampler(t *testing.T, interval time.Duration, sampling LevelSampling) (*slog.Logger, *recordingHandler, func() error) {
+	t.Helper()
+
+	recorder := &recordingHandler{}
+	handler, closer, err := newSamplingHandler(&SamplingConfig{
+		Interval: interval,
+		Levels:   map[string]LevelSampling{"info": sampling},
+	}, recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { closer.Close() })
+	return slog.New(handler), recorder, closer.Close
+}
+
+func TestSamplingPassesFirstAndThereafter(t *testing.T) {
+	log, recorder, closeSampler := newTestSampler(t, time.Hour, LevelSampling{First: 1, Thereafter: 3})
+
+	for range 10 {
+		log.Info("flood")
+	}
+	// other levels are not sampled
+	for range 3 {
+		log.Warn("flood")
+	}
+	closeSampler()
+
+	// the 1st, 4th, 7th and 10th info records and every warning
+	passed, suppressed := recorder.split("flood")
+	if passed != 7 {
+		t.Errorf("passed %d records, want 7", passed)
+	}
+	if len(suppressed) != 1 || suppressed[0] != 6 {
+		t.Errorf("summaries %v, want one of 6 suppressed", suppressed)
+	}
+}
+
+func TestSamplingSummaryWithoutNewRecords(t *testing.T) {
+	log, recorder, _ := newTestSampler(t, 100*time.Millisecond, LevelSampling{First: 2})
+
+	for range 10 {
+		log.Info("flood")
+	}
+
+	// the flood has stopped, the timer still reports it
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		passed, suppressed := recorder.split("flood")
+		if len(suppressed) > 0 {
+			if passed != 2 || len(suppressed) != 1 || suppressed[0] != 8 {
+				t.Fatalf("passed %d with summaries %v, want 2 and one of 8 suppressed", passed, suppressed)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no summary written after the interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// a new interval starts from zero
+	log.Info("flood")
+	if passed, _ := recorder.split("flood"); passed != 3 {
+		t.Fatalf("passed %d records, want 3", passed)
+	}
+}
+
+func TestSamplingCloseIsIdempotent(t *testing.T) {
+	_, _, closeSampler := newTestSampler(t, time.Hour, LevelSampling{First: 1})
+	if err := closeSampler(); err != nil {
+		t.Fatal(err)
+	}
+	if err := closeSampler(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,223 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	syslogDialTimeout = 5 * time.Second
+	// syslogRetryDelay пауза между попытками подключения к недоступному коллектору
+	syslogRetryDelay = time.Second
+)
+
+// syslogSeverities уровни RFC 5424, в которые отображаются уровни slog
+var syslogSeverities = []int{3, 4, 6, 7}
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogSeverity соответствие уровней slog уровням RFC 5424
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3
+	case level >= slog.LevelWarn:
+		return 4
+	case level >= slog.LevelInfo:
+		return 6
+	default:
+		return 7
+	}
+}
+
+// syslogWriter отправляет каждую запись отдельным сообщением RFC 5424.
+// Для потоковых соединений (tcp, unix) используется octet counting из RFC 6587.
+// Соединение устанавливается при первой записи и восстанавливается после
+// обрыва, так что недоступный коллектор не мешает запуску
+type syslogWriter struct {
+	network  string
+	address  string
+	facility int
+	hostname string
+	appName  string
+	pid      string
+
+	mutex   sync.Mutex
+	conn    net.Conn
+	retryAt time.Time
+}
+
+func newSyslogWriter(cfg *SyslogConfig) (*syslogWriter, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("syslog address is required for syslog output")
+	}
+
+	w := &syslogWriter{
+		network:  cfg.Network,
+		address:  cfg.Address,
+		facility: syslogFacilities["user"],
+		appName:  cfg.AppName,
+		pid:      strconv.Itoa(os.Getpid()),
+	}
+	if w.network == "" {
+		w.network = "udp"
+	}
+	if cfg.Facility != "" {
+		facility, ok := syslogFacilities[cfg.Facility]
+		if !ok {
+			return nil, fmt.Errorf("unknown syslog facility %q", cfg.Facility)
+		}
+		w.facility = facility
+	}
+	if w.appName == "" {
+		w.appName = filepath.Base(os.Args[0])
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		w.hostname = hostname
+	} else {
+		w.hostname = "-"
+	}
+
+	return w, nil
+}
+
+// connect подключается к коллектору не чаще раза в syslogRetryDelay, чтобы
+// при его недоступности запись не ждала таймаута на каждом сообщении
+func (w *syslogWriter) connect() error {
+	if time.Now().Before(w.retryAt) {
+		return fmt.Errorf("syslog %s://%s is unavailable", w.network, w.address)
+	}
+	conn, err := net.DialTimeout(w.network, w.address, syslogDialTimeout)
+	if err != nil {
+		w.retryAt = time.Now().Add(syslogRetryDelay)
+		err = fmt.Errorf("failed to connect to syslog %s://%s: %w", w.network, w.address, err)
+		fmt.Fprintf(os.Stderr, "logger: %v\n", err)
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+func (w *syslogWriter) stream() bool {
+	return w.network == "tcp" || w.network == "unix"
+}
+
+// Write отправляет сообщение с severity info. Обработчик sink-а передает
+// severity каждой записи через severityWriter
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	return w.write(syslogSeverity(slog.LevelInfo), p)
+}
+
+func (w *syslogWriter) write(severity int, p []byte) (int, error) {
+	msg := bytes.TrimRight(p, "\n")
+	frame := fmt.Appendf(nil, "<%d>1 %s %s %s %s - - ",
+		w.facility*8+severity,
+		time.Now().Format(time.RFC3339Nano),
+		w.hostname, w.appName, w.pid,
+	)
+	frame = append(frame, msg...)
+	if w.stream() {
+		frame = append(fmt.Appendf(nil, "%d ", len(frame)), frame...)
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := w.conn.Write(frame); err != nil {
+		// одна попытка переподключения, например после рестарта коллектора
+		w.conn.Close()
+		w.conn = nil
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+		if _, err := w.conn.Write(frame); err != nil {
+			w.conn.Close()
+			w.conn = nil
+			return 0, fmt.Errorf("failed to write to syslog: %w", err)
+		}
+	}
+	return len(p), nil
+}
+
+func (w *syslogWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// severityWriter передает в syslogWriter severity, к которой привязан обработчик
+type severityWriter struct {
+	writer   *syslogWriter
+	severity int
+}
+
+func (w severityWriter) Write(p []byte) (int, error) {
+	return w.writer.write(w.severity, p)
+}
+
+// syslogHandler форматирует запись обработчиком, привязанным к severity ее
+// уровня, так что severity передается вместе с каждой записью
+type syslogHandler struct {
+	handlers map[int]slog.Handler
+}
+
+func newSyslogHandler(w *syslogWriter, newHandler func(io.Writer) (slog.Handler, error)) (*syslogHandler, error) {
+	sh := &syslogHandler{handlers: make(map[int]slog.Handler, len(syslogSeverities))}
+	for _, severity := range syslogSeverities {
+		handler, err := newHandler(severityWriter{writer: w, severity: severity})
+		if err != nil {
+			return nil, err
+		}
+		sh.handlers[severity] = handler
+	}
+	return sh, nil
+}
+
+func (sh *syslogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return sh.handlers[syslogSeverity(level)].Enabled(ctx, level)
+}
+
+func (sh *syslogHandler) Handle(ctx context.Context, r slog.Record) error {
+	return sh.handlers[syslogSeverity(r.Level)].Handle(ctx, r)
+}
+
+func (sh *syslogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return sh.derive(func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+func (sh *syslogHandler) WithGroup(name string) slog.Handler {
+	return sh.derive(func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+func (sh *syslogHandler) derive(f func(slog.Handler) slog.Handler) slog.Handler {
+	derived := &syslogHandler{handlers: make(map[int]slog.Handler, len(sh.handlers))}
+	for severity, handler := range sh.handlers {
+		derived.handlers[severity] = f(handler)
+	}
+	return derived
+}
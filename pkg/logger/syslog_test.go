@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogSeverityPerRecord(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	handler, closer, err := newSinkHandler(&SinkConfig{
+		Output: "syslog",
+		Format: "json",
+		Syslog: SyslogConfig{Network: "udp", Address: conn.LocalAddr().String(), Facility: "local0", AppName: "test"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closer.Close()
+
+	log := slog.New(handler).With("component", "test")
+	log.Error("failed")
+	log.Debug("details")
+
+	// local0 is facility 16
+	for _, want := range []string{"<131>1 ", "<135>1 "} {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		buf := make([]byte, 4096)
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := string(buf[:n])
+		if !strings.HasPrefix(msg, want) {
+			t.Fatalf("message %q, want prefix %q", msg, want)
+		}
+		if !strings.Contains(msg, " test ") || !strings.Contains(msg, `"component":"test"`) {
+			t.Fatalf("message %q lacks the app name or the attributes", msg)
+		}
+	}
+}
+
+func TestSyslogDialsLazily(t *testing.T) {
+	// an address nobody listens on yet
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := lis.Addr().String()
+	lis.Close()
+
+	w, err := newSyslogWriter(&SyslogConfig{Network: "tcp", Address: address})
+	if err != nil {
+		t.Fatalf("newSyslogWriter with the collector down: %v", err)
+	}
+	defer w.Close()
+
+	if _, err := w.write(6, []byte("lost\n")); err == nil {
+		t.Fatal("write with the collector down succeeded")
+	}
+	// within the retry delay the writer does not dial again
+	if _, err := w.write(6, []byte("lost\n")); err == nil || !strings.Contains(err.Error(), "unavailable") {
+		t.Fatalf("write within the retry delay = %v, want unavailable", err)
+	}
+
+	lis, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("address %s was taken: %v", address, err)
+	}
+	defer lis.Close()
+
+	w.mutex.Lock()
+	w.retryAt = time.Time{}
+	w.mutex.Unlock()
+	if _, err := w.write(4, []byte("delivered\n")); err != nil {
+		t.Fatalf("write after the collector started: %v", err)
+	}
+
+	lis.(*net.TCPListener).SetDeadline(time.Now().Add(5 * time.Second))
+	conn, err := lis.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	// octet counting: the length, a space, then the message
+	reader := bufio.NewReader(conn)
+	length, err := reader.ReadString(' ')
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(length))
+	if err != nil {
+		t.Fatalf("frame length %q: %v", length, err)
+	}
+	frame := make([]byte, n)
+	if _, err := io.ReadFull(reader, frame); err != nil {
+		t.Fatal(err)
+	}
+	// facility user, severity warning
+	if msg := string(frame); !strings.HasPrefix(msg, "<12>1 ") || !strings.HasSuffix(msg, " - - delivered") {
+		t.Fatalf("frame %q, want a warning of facility user", msg)
+	}
+}
@@ -2,8 +2,12 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,11 +19,20 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+var retryCodes = []codes.Code{codes.Unavailable, codes.ResourceExhausted}
+
 const (
 	maxRetry     uint          = 5
 	retryTimeOut time.Duration = time.Millisecond * 100
+
+	closeAllTimeout = 30 * time.Second
+	// closeGracePeriod is how long Shutdown still collects close results once its context is done
+	closeGracePeriod = time.Second
 )
 
+// ErrManagerClosed is returned once the manager has been shut down.
+var ErrManagerClosed = errors.New("grpc client manager is closed")
+
 type GrpcClientInterface interface {
 	Initialize(conn *grpc.ClientConn) error
 	Close() error
@@ -30,27 +43,48 @@ type GrpcClientInterface interface {
 // GrpcClientManager manages gRPC clients
 // It allows registering, retrieving, and closing clients.
 type GrpcClientManager struct {
-	clients map[string]GrpcClientInterface
-	mutex   sync.RWMutex
-	log     logger.LoggerInterface
+	clients  map[string]GrpcClientInterface
+	watchers map[string]context.CancelFunc
+	mutex    sync.RWMutex
+	log      logger.LoggerInterface
+	calls    *callTracker
+	events   eventBus
+	closed   bool
 }
 
+// Component is the logger component of the manager, whose level can be set in
+// logger.components and changed at runtime.
+const Component = "manager"
+
+// NewGrpcClientManager creates a manager that logs to log, or to the Component
+// logger when log is nil.
 func NewGrpcClientManager(log logger.LoggerInterface) *GrpcClientManager {
+	if log == nil {
+		log = logger.Component(Component)
+	}
 	return &GrpcClientManager{
-		clients: make(map[string]GrpcClientInterface),
-		log:     log,
+		clients:  make(map[string]GrpcClientInterface),
+		watchers: make(map[string]context.CancelFunc),
+		log:      log,
+		calls:    newCallTracker(),
 	}
 }
 
-func (cm *GrpcClientManager) RegisterClient(client GrpcClientInterface) error {
+// RegisterClient connects and initializes the client. Options customize the
+// connection of this client only and are applied on top of the manager defaults.
+func (cm *GrpcClientManager) RegisterClient(client GrpcClientInterface, opts ...ClientOption) error {
 	cm.mutex.Lock()
 	defer cm.mutex.Unlock()
 
+	if cm.closed {
+		return ErrManagerClosed
+	}
+
 	if _, exists := cm.clients[client.GetName()]; exists {
 		return fmt.Errorf("client '%s' is already registered", client.GetName())
 	}
 
-	conn, err := cm.createConnection(client.GetHost())
+	conn, err := cm.createConnection(client.GetName(), client.GetHost(), newClientOptions(opts))
 	if err != nil {
 		return fmt.Errorf("failed to create connection for client '%s': %w", client.GetName(), err)
 	}
@@ -60,8 +94,13 @@ func (cm *GrpcClientManager) RegisterClient(client GrpcClientInterface) error {
 		return fmt.Errorf("failed to initialize client '%s': %w", client.GetName(), err)
 	}
 
+	watchCtx, cancel := context.WithCancel(context.Background())
+	go cm.watchState(watchCtx, client.GetName(), conn)
+
 	cm.clients[client.GetName()] = client
-	cm.log.Info("Successfully registered gRPC client: %s", client.GetName())
+	cm.watchers[client.GetName()] = cancel
+	cm.log.Info("Successfully registered gRPC client", logger.Client(client.GetName()))
+	cm.emit(Event{Type: EventRegistered, Client: client.GetName()})
 	return nil
 }
 
@@ -69,6 +108,10 @@ func (cm *GrpcClientManager) GetClient(name string) (GrpcClientInterface, error)
 	cm.mutex.RLock()
 	defer cm.mutex.RUnlock()
 
+	if cm.closed {
+		return nil, ErrManagerClosed
+	}
+
 	client, exists := cm.clients[name]
 	if !exists {
 		return nil, fmt.Errorf("client '%s' not found", name)
@@ -76,18 +119,98 @@ func (cm *GrpcClientManager) GetClient(name string) (GrpcClientInterface, error)
 	return client, nil
 }
 
+// CloseAll shuts the manager down, waiting at most closeAllTimeout for calls
+// to drain and connections to close, and logs any close errors.
 func (cm *GrpcClientManager) CloseAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), closeAllTimeout)
+	defer cancel()
+
+	if err := cm.Shutdown(ctx); err != nil && !errors.Is(err, ErrManagerClosed) {
+		cm.log.Error("Error closing gRPC clients", logger.Err(err))
+	}
+}
+
+// Shutdown stops handing out clients, waits for in-flight calls to finish and
+// then closes every connection in parallel. If ctx expires before the calls
+// drain, the connections are closed anyway and the context error is included
+// in the result. The returned error joins the failures of every client and
+// names the clients whose close did not finish within closeGracePeriod after
+// ctx expired. EventClosed is published for every client, also after a late close.
+func (cm *GrpcClientManager) Shutdown(ctx context.Context) error {
 	cm.mutex.Lock()
-	defer cm.mutex.Unlock()
+	if cm.closed {
+		cm.mutex.Unlock()
+		return ErrManagerClosed
+	}
+	cm.closed = true
+	clients := cm.clients
+	cm.clients = make(map[string]GrpcClientInterface)
+	for _, cancel := range cm.watchers {
+		cancel()
+	}
+	cm.watchers = make(map[string]context.CancelFunc)
+	cm.mutex.Unlock()
+
+	var errs []error
+	if err := cm.calls.wait(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("waiting for in-flight calls: %w", err))
+	}
 
-	for name, client := range cm.clients {
-		if err := client.Close(); err != nil {
-			cm.log.Error("Error closing client '%s': %v", name, err)
-		} else {
-			cm.log.Info("Closed gRPC client: %s", name)
+	results := make(chan closeResult, len(clients))
+	pending := make(map[string]bool, len(clients))
+	for name, client := range clients {
+		pending[name] = true
+		go func() {
+			results <- closeResult{name: name, err: client.Close()}
+		}()
+	}
+
+	// once ctx is done the closes get closeGracePeriod to report their result
+	done := ctx.Done()
+	var grace <-chan time.Time
+	for len(pending) > 0 {
+		select {
+		case res := <-results:
+			delete(pending, res.name)
+			if err := cm.reportClose(res); err != nil {
+				errs = append(errs, err)
+			}
+		case <-done:
+			done = nil
+			timer := time.NewTimer(closeGracePeriod)
+			defer timer.Stop()
+			grace = timer.C
+		case <-grace:
+			names := slices.Sorted(maps.Keys(pending))
+			errs = append(errs, fmt.Errorf("clients not closed in time (%s): %w", strings.Join(names, ", "), ctx.Err()))
+			// late closes are still logged and published
+			go func() {
+				for range names {
+					cm.reportClose(<-results)
+				}
+			}()
+			return errors.Join(errs...)
 		}
 	}
-	cm.clients = make(map[string]GrpcClientInterface)
+
+	return errors.Join(errs...)
+}
+
+type closeResult struct {
+	name string
+	err  error
+}
+
+// reportClose logs and publishes the result of closing a client and returns the
+// error to report.
+func (cm *GrpcClientManager) reportClose(res closeResult) error {
+	cm.emit(Event{Type: EventClosed, Client: res.name, Err: res.err})
+	if res.err != nil {
+		cm.log.Error("Error closing gRPC client", logger.Client(res.name), logger.Err(res.err))
+		return fmt.Errorf("client '%s': %w", res.name, res.err)
+	}
+	cm.log.Info("Closed gRPC client", logger.Client(res.name))
+	return nil
 }
 
 func (cm *GrpcClientManager) ListClients() []string {
@@ -101,9 +224,9 @@ func (cm *GrpcClientManager) ListClients() []string {
 	return names
 }
 
-func (cm *GrpcClientManager) createConnection(address string) (*grpc.ClientConn, error) {
+func (cm *GrpcClientManager) createConnection(name, address string, clientOpts *clientOptions) (*grpc.ClientConn, error) {
 	retryOpts := []retry.CallOption{
-		retry.WithCodes(codes.Unavailable, codes.ResourceExhausted),
+		retry.WithCodes(retryCodes...),
 		retry.WithMax(maxRetry),
 		retry.WithBackoff(retry.BackoffLinear(retryTimeOut)),
 	}
@@ -115,10 +238,16 @@ func (cm *GrpcClientManager) createConnection(address string) (*grpc.ClientConn,
 	grpcOpts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithChainUnaryInterceptor(
+			cm.calls.unaryInterceptor(),
 			grpclog.UnaryClientInterceptor(cm.logInterceptor(), logOpts...),
+			cm.retryExhaustedInterceptor(name),
 			retry.UnaryClientInterceptor(retryOpts...),
 		),
+		grpc.WithChainStreamInterceptor(
+			cm.calls.streamInterceptor(),
+		),
 	}
+	grpcOpts = append(grpcOpts, clientOpts.dial()...)
 
 	conn, err := grpc.NewClient(address, grpcOpts...)
 	if err != nil {
@@ -128,6 +257,9 @@ func (cm *GrpcClientManager) createConnection(address string) (*grpc.ClientConn,
 	return conn, nil
 }
 
+// logInterceptor logs the gRPC events. Fields of the payloads annotated as
+// sensitive in the proto definitions are masked by the logger pipeline,
+// as configured under logger.redact.
 func (cm *GrpcClientManager) logInterceptor() grpclog.Logger {
 	return grpclog.LoggerFunc(func(ctx context.Context, lvl grpclog.Level, msg string, fields ...any) {
 		cm.log.Log(ctx, slog.Level(lvl), msg, fields...)
@@ -0,0 +1,157 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// fakeClient is a managed client whose Close takes closeDelay and fails with closeErr.
+type fakeClient struct {
+	name       string
+	closeDelay time.Duration
+	closeErr   error
+	conn       *grpc.ClientConn
+}
+
+func (c *fakeClient) Initialize(conn *grpc.ClientConn) error {
+	c.conn = conn
+	return nil
+}
+
+func (c *fakeClient) Close() error {
+	time.Sleep(c.closeDelay)
+	c.conn.Close()
+	return c.closeErr
+}
+
+func (c *fakeClient) GetName() string { return c.name }
+func (c *fakeClient) GetHost() string { return "passthrough:///" + c.name }
+
+func newTestManager(t *testing.T, clients ...*fakeClient) *GrpcClientManager {
+	t.Helper()
+
+	cm := NewGrpcClientManager(nil)
+	for _, client := range clients {
+		if err := cm.RegisterClient(client); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cm
+}
+
+// closedEvents collects the EventClosed events of sub until want arrived.
+func closedEvents(t *testing.T, sub *Subscription, want int) map[string]error {
+	t.Helper()
+
+	events := map[string]error{}
+	timeout := time.After(5 * time.Second)
+	for len(events) < want {
+		select {
+		case event := <-sub.Events():
+			if event.Type == EventClosed {
+				events[event.Client] = event.Err
+			}
+		case <-timeout:
+			t.Fatalf("got %d EventClosed events, want %d", len(events), want)
+		}
+	}
+	return events
+}
+
+func TestShutdownJoinsClientErrors(t *testing.T) {
+	errA, errB := errors.New("a failed"), errors.New("b failed")
+	cm := newTestManager(t,
+		&fakeClient{name: "a", closeErr: errA},
+		&fakeClient{name: "b", closeErr: errB},
+		&fakeClient{name: "c"},
+	)
+
+	err := cm.Shutdown(context.Background())
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("Shutdown = %v, want the errors of a and b", err)
+	}
+	if strings.Contains(err.Error(), "'c'") {
+		t.Fatalf("Shutdown = %v, want no error for c", err)
+	}
+}
+
+func TestShutdownAfterDrainDeadline(t *testing.T) {
+	errA, errB := errors.New("a failed"), errors.New("b failed")
+	cm := newTestManager(t,
+		&fakeClient{name: "a", closeDelay: 20 * time.Millisecond, closeErr: errA},
+		&fakeClient{name: "b", closeDelay: 20 * time.Millisecond, closeErr: errB},
+	)
+	sub := cm.Subscribe(0)
+	defer sub.Unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := cm.Shutdown(ctx)
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("Shutdown = %v, want the errors of a and b", err)
+	}
+
+	events := closedEvents(t, sub, 2)
+	if events["a"] != errA || events["b"] != errB {
+		t.Fatalf("EventClosed errors = %v", events)
+	}
+}
+
+func TestShutdownNamesPendingClients(t *testing.T) {
+	cm := newTestManager(t,
+		&fakeClient{name: "fast"},
+		&fakeClient{name: "slow", closeDelay: closeGracePeriod + 500*time.Millisecond},
+	)
+	sub := cm.Subscribe(0)
+	defer sub.Unsubscribe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := cm.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "(slow)") {
+		t.Fatalf("Shutdown = %v, want slow named as not closed", err)
+	}
+
+	// the late close is still published
+	closedEvents(t, sub, 2)
+}
+
+func TestShutdownClosesInParallel(t *testing.T) {
+	const delay = 100 * time.Millisecond
+	var clients []*fakeClient
+	for i := range 5 {
+		clients = append(clients, &fakeClient{name: fmt.Sprint("client", i), closeDelay: delay})
+	}
+	cm := newTestManager(t, clients...)
+
+	start := time.Now()
+	if err := cm.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*delay {
+		t.Fatalf("Shutdown took %v, want the closes to run in parallel", elapsed)
+	}
+}
+
+func TestManagerClosed(t *testing.T) {
+	cm := newTestManager(t, &fakeClient{name: "a"})
+	if err := cm.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cm.GetClient("a"); !errors.Is(err, ErrManagerClosed) {
+		t.Errorf("GetClient = %v, want ErrManagerClosed", err)
+	}
+	if err := cm.RegisterClient(&fakeClient{name: "b"}); !errors.Is(err, ErrManagerClosed) {
+		t.Errorf("RegisterClient = %v, want ErrManagerClosed", err)
+	}
+	if err := cm.Shutdown(context.Background()); !errors.Is(err, ErrManagerClosed) {
+		t.Errorf("second Shutdown = %v, want ErrManagerClosed", err)
+	}
+}
@@ -0,0 +1,172 @@
+package manager
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+const defaultEventBuffer = 64
+
+// EventType identifies what happened to a managed client.
+type EventType int
+
+const (
+	// EventRegistered is emitted after a client has been registered and initialized.
+	EventRegistered EventType = iota + 1
+	// EventStateChanged is emitted whenever the connectivity state of a client's connection changes.
+	EventStateChanged
+	// EventClosed is emitted after a client has been closed during shutdown.
+	EventClosed
+	// EventRetryExhausted is emitted when a call still fails with a retryable code after all retries.
+	EventRetryExhausted
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventRegistered:
+		return "registered"
+	case EventStateChanged:
+		return "state_changed"
+	case EventClosed:
+		return "closed"
+	case EventRetryExhausted:
+		return "retry_exhausted"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a lifecycle change of a managed client.
+type Event struct {
+	Type   EventType
+	Client string
+	Time   time.Time
+	// State is set for EventStateChanged.
+	State connectivity.State
+	// Method is set for EventRetryExhausted.
+	Method string
+	// Err is set for EventRetryExhausted and for EventClosed when closing failed.
+	Err error
+}
+
+// Subscription receives manager events until it is unsubscribed.
+// Delivery never blocks the manager: events that do not fit into the
+// subscription buffer are dropped and counted.
+type Subscription struct {
+	events  chan Event
+	dropped atomic.Uint64
+	owner   *eventBus
+	once    sync.Once
+}
+
+// Events returns the channel the events are delivered on.
+// The channel is closed by Unsubscribe.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped returns the number of events lost because the buffer was full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe stops the delivery and closes the events channel.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.owner.remove(s)
+	})
+}
+
+type eventBus struct {
+	mutex sync.Mutex
+	subs  []*Subscription
+}
+
+func (b *eventBus) add(buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = defaultEventBuffer
+	}
+	sub := &Subscription{events: make(chan Event, buffer), owner: b}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.subs = append(b.subs, sub)
+	return sub
+}
+
+func (b *eventBus) remove(sub *Subscription) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.subs = slices.DeleteFunc(b.subs, func(s *Subscription) bool { return s == sub })
+	close(sub.events)
+}
+
+func (b *eventBus) publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, sub := range b.subs {
+		select {
+		case sub.events <- event:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribe returns a subscription buffering up to buffer events.
+// A non-positive buffer selects the default size.
+func (cm *GrpcClientManager) Subscribe(buffer int) *Subscription {
+	return cm.events.add(buffer)
+}
+
+// OnEvent invokes fn for every event on a dedicated goroutine, so a slow
+// callback only delays its own events. Call Unsubscribe to stop it.
+func (cm *GrpcClientManager) OnEvent(fn func(Event)) *Subscription {
+	sub := cm.events.add(defaultEventBuffer)
+	go func() {
+		for event := range sub.events {
+			fn(event)
+		}
+	}()
+	return sub
+}
+
+func (cm *GrpcClientManager) emit(event Event) {
+	cm.events.publish(event)
+}
+
+// watchState publishes connectivity changes of conn until ctx is cancelled.
+func (cm *GrpcClientManager) watchState(ctx context.Context, name string, conn *grpc.ClientConn) {
+	state := conn.GetState()
+	for conn.WaitForStateChange(ctx, state) {
+		state = conn.GetState()
+		cm.log.Debug("gRPC client changed state", logger.Client(name), "state", state.String())
+		cm.emit(Event{Type: EventStateChanged, Client: name, State: state})
+	}
+}
+
+// retryExhaustedInterceptor must be placed before the retry interceptor: a
+// retryable code reaching it means every attempt has failed.
+func (cm *GrpcClientManager) retryExhaustedInterceptor(name string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err != nil && slices.Contains(retryCodes, status.Code(err)) {
+			cm.emit(Event{Type: EventRetryExhausted, Client: name, Method: method, Err: err})
+		}
+		return err
+	}
+}
@@ -0,0 +1,193 @@
+package manager
+
+import (
+	"context"
+	"net"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/helloworld"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestSubscribeDelivers(t *testing.T) {
+	cm := NewGrpcClientManager(nil)
+	sub := cm.Subscribe(0)
+
+	cm.emit(Event{Type: EventRegistered, Client: "a"})
+	select {
+	case event := <-sub.Events():
+		if event.Type != EventRegistered || event.Client != "a" || event.Time.IsZero() {
+			t.Fatalf("event = %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not delivered")
+	}
+
+	sub.Unsubscribe()
+	if _, ok := <-sub.Events(); ok {
+		t.Fatal("events channel still open after Unsubscribe")
+	}
+	// a second Unsubscribe is a no-op
+	sub.Unsubscribe()
+}
+
+func TestSubscriptionDropsWhenFull(t *testing.T) {
+	cm := NewGrpcClientManager(nil)
+	sub := cm.Subscribe(2)
+	defer sub.Unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		for range 5 {
+			cm.emit(Event{Type: EventStateChanged, Client: "a"})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("publishing blocked on a full subscription")
+	}
+
+	if dropped := sub.Dropped(); dropped != 3 {
+		t.Fatalf("dropped = %d, want 3", dropped)
+	}
+	if n := len(sub.Events()); n != 2 {
+		t.Fatalf("buffered = %d, want 2", n)
+	}
+}
+
+func TestOnEvent(t *testing.T) {
+	cm := NewGrpcClientManager(nil)
+	received := make(chan Event, 2)
+	before := runtime.NumGoroutine()
+	sub := cm.OnEvent(func(event Event) {
+		received <- event
+	})
+
+	cm.emit(Event{Type: EventRegistered, Client: "a"})
+	select {
+	case event := <-received:
+		if event.Client != "a" {
+			t.Fatalf("event = %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback not invoked")
+	}
+
+	sub.Unsubscribe()
+	cm.emit(Event{Type: EventRegistered, Client: "b"})
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatal("the callback goroutine is still running after Unsubscribe")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(received) != 0 {
+		t.Fatalf("callback invoked after Unsubscribe: %+v", <-received)
+	}
+}
+
+func TestEventClosedOnShutdown(t *testing.T) {
+	cm := newTestManager(t, &fakeClient{name: "a"}, &fakeClient{name: "b"})
+	sub := cm.Subscribe(0)
+	defer sub.Unsubscribe()
+
+	if err := cm.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	events := closedEvents(t, sub, 2)
+	if events["a"] != nil || events["b"] != nil {
+		t.Fatalf("EventClosed errors = %v, want none", events)
+	}
+}
+
+// greeter fails the first failures calls with Unavailable and records the
+// metadata of the last call.
+type greeter struct {
+	helloworld.UnimplementedGreeterServer
+	calls    atomic.Int32
+	failures int32
+	incoming atomic.Value
+}
+
+func (g *greeter) SayHello(ctx context.Context, in *helloworld.HelloRequest) (*helloworld.HelloReply, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	g.incoming.Store(md)
+	grpc.SetHeader(ctx, metadata.Pairs("x-greeter", "hello"))
+	if g.calls.Add(1) <= g.failures {
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	}
+	return &helloworld.HelloReply{Message: "Hello " + in.GetName()}, nil
+}
+
+func (g *greeter) metadata() metadata.MD {
+	md, _ := g.incoming.Load().(metadata.MD)
+	return md
+}
+
+func dialGreeter(t *testing.T, g *greeter) ClientOption {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	helloworld.RegisterGreeterServer(server, g)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}))
+}
+
+func TestEventRetryExhausted(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int32
+		wantCalls  int32
+		wantEvents int
+	}{
+		{name: "recovers", failures: 2, wantCalls: 3, wantEvents: 0},
+		{name: "exhausted", failures: 100, wantCalls: int32(maxRetry), wantEvents: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &greeter{failures: tt.failures}
+			client := &fakeClient{name: "greeter"}
+			cm := NewGrpcClientManager(nil)
+			if err := cm.RegisterClient(client, dialGreeter(t, g)); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { cm.Shutdown(context.Background()) })
+			sub := cm.Subscribe(0)
+			defer sub.Unsubscribe()
+
+			helloworld.NewGreeterClient(client.conn).SayHello(context.Background(), &helloworld.HelloRequest{Name: "a"})
+			if calls := g.calls.Load(); calls != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+
+			var exhausted []Event
+			for len(sub.Events()) > 0 {
+				if event := <-sub.Events(); event.Type == EventRetryExhausted {
+					exhausted = append(exhausted, event)
+				}
+			}
+			if len(exhausted) != tt.wantEvents {
+				t.Fatalf("EventRetryExhausted = %v, want %d", exhausted, tt.wantEvents)
+			}
+			if tt.wantEvents > 0 && (exhausted[0].Method != "/helloworld.Greeter/SayHello" || status.Code(exhausted[0].Err) != codes.Unavailable) {
+				t.Fatalf("event = %+v", exhausted[0])
+			}
+		})
+	}
+}
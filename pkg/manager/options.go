@@ -0,0 +1,78 @@
+package manager
+
+import "google.golang.org/grpc"
+
+// ClientOption customizes the connection created for a single client.
+// Everything configured through options is appended to the manager's defaults.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
+	dialOptions        []grpc.DialOption
+	callOptions        []grpc.CallOption
+	userAgent          string
+}
+
+// WithUnaryInterceptors adds unary interceptors after the default chain,
+// so they run once per attempt when the call is retried.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) ClientOption {
+	return func(o *clientOptions) {
+		o.unaryInterceptors = append(o.unaryInterceptors, interceptors...)
+	}
+}
+
+// WithStreamInterceptors adds stream interceptors after the default chain.
+func WithStreamInterceptors(interceptors ...grpc.StreamClientInterceptor) ClientOption {
+	return func(o *clientOptions) {
+		o.streamInterceptors = append(o.streamInterceptors, interceptors...)
+	}
+}
+
+// WithDialOptions adds raw dial options, e.g. per-RPC credentials.
+func WithDialOptions(opts ...grpc.DialOption) ClientOption {
+	return func(o *clientOptions) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+// WithUserAgent sets the user agent sent by the client.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithDefaultCallOptions sets call options applied to every call of the client,
+// e.g. grpc.UseCompressor or grpc.MaxCallRecvMsgSize.
+func WithDefaultCallOptions(opts ...grpc.CallOption) ClientOption {
+	return func(o *clientOptions) {
+		o.callOptions = append(o.callOptions, opts...)
+	}
+}
+
+func newClientOptions(opts []ClientOption) *clientOptions {
+	o := &clientOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// dial converts the options into dial options to be appended to the defaults.
+func (o *clientOptions) dial() []grpc.DialOption {
+	var opts []grpc.DialOption
+	if len(o.unaryInterceptors) > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(o.unaryInterceptors...))
+	}
+	if len(o.streamInterceptors) > 0 {
+		opts = append(opts, grpc.WithChainStreamInterceptor(o.streamInterceptors...))
+	}
+	if o.userAgent != "" {
+		opts = append(opts, grpc.WithUserAgent(o.userAgent))
+	}
+	if len(o.callOptions) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(o.callOptions...))
+	}
+	return append(opts, o.dialOptions...)
+}
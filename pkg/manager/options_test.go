@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/helloworld"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestClientOptionsApplyPerClient(t *testing.T) {
+	var attempts atomic.Int32
+	count := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		attempts.Add(1)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	var header metadata.MD
+
+	// the first two calls of a fail, so a retries
+	ga, gb := &greeter{failures: 2}, &greeter{}
+	a, b := &fakeClient{name: "a"}, &fakeClient{name: "b"}
+	cm := NewGrpcClientManager(nil)
+	t.Cleanup(func() { cm.Shutdown(context.Background()) })
+	err := cm.RegisterClient(a,
+		dialGreeter(t, ga),
+		WithUnaryInterceptors(count),
+		WithUserAgent("client-a"),
+		WithDefaultCallOptions(grpc.Header(&header)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cm.RegisterClient(b, dialGreeter(t, gb)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err := helloworld.NewGreeterClient(a.conn).SayHello(ctx, &helloworld.HelloRequest{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	// the interceptor runs inside the retry of the default chain: once per attempt
+	if n := attempts.Load(); n != 3 {
+		t.Fatalf("interceptor of a ran %d times, want once per attempt (3)", n)
+	}
+	if got := header.Get("x-greeter"); len(got) != 1 {
+		t.Fatalf("header of a = %v, want the call option to record it", header)
+	}
+	if ua := strings.Join(ga.metadata().Get("user-agent"), ","); !strings.HasPrefix(ua, "client-a ") {
+		t.Fatalf("user agent of a = %q, want the client-a prefix", ua)
+	}
+
+	header = nil
+	if _, err := helloworld.NewGreeterClient(b.conn).SayHello(ctx, &helloworld.HelloRequest{Name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := attempts.Load(); n != 3 {
+		t.Fatalf("interceptor of a ran for b")
+	}
+	if header != nil {
+		t.Fatalf("call option of a applied to b: header = %v", header)
+	}
+	if ua := strings.Join(gb.metadata().Get("user-agent"), ","); strings.Contains(ua, "client-a") {
+		t.Fatalf("user agent of b = %q, want the default", ua)
+	}
+}
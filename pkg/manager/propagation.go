@@ -0,0 +1,66 @@
+package manager
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// LogFieldMetadataPrefix prefixes the metadata keys of propagated log fields.
+const LogFieldMetadataPrefix = "x-log-"
+
+// WithLogFieldPropagation forwards the log fields stored in the call context
+// (see logger.AppendCtx) as outgoing metadata named LogFieldMetadataPrefix+key.
+// Without keys every non-group field is propagated.
+func WithLogFieldPropagation(keys ...string) ClientOption {
+	return func(o *clientOptions) {
+		o.unaryInterceptors = append(o.unaryInterceptors, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(withLogFields(ctx, keys), method, req, reply, cc, opts...)
+		})
+		o.streamInterceptors = append(o.streamInterceptors, func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(withLogFields(ctx, keys), desc, cc, method, opts...)
+		})
+	}
+}
+
+func withLogFields(ctx context.Context, keys []string) context.Context {
+	fields := logger.FieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	pairs := make([]string, 0, 2*len(fields))
+	for _, field := range fields {
+		if len(keys) > 0 && !slices.Contains(keys, field.Key) {
+			continue
+		}
+		value := field.Value.Resolve()
+		if value.Kind() == slog.KindGroup {
+			continue
+		}
+		pairs = append(pairs, LogFieldMetadataPrefix+metadataKey(field.Key), value.String())
+	}
+	if len(pairs) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// metadataKey maps a log key to the characters allowed in metadata keys.
+func metadataKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, key)
+}
@@ -0,0 +1,63 @@
+package manager
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/MagicRodri/grpc_with_go/pkg/generated/helloworld"
+	"github.com/MagicRodri/grpc_with_go/pkg/logger"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestWithLogFields(t *testing.T) {
+	ctx := logger.AppendCtxAttrs(context.Background(),
+		slog.String("request_id", "r1"),
+		slog.String("User ID", "42"),
+		slog.Group("http", slog.String("method", "GET")),
+	)
+
+	tests := []struct {
+		name string
+		keys []string
+		want map[string][]string
+	}{
+		{name: "all", want: map[string][]string{"x-log-request_id": {"r1"}, "x-log-user-id": {"42"}}},
+		{name: "selected", keys: []string{"request_id"}, want: map[string][]string{"x-log-request_id": {"r1"}}},
+		{name: "none matching", keys: []string{"missing"}, want: map[string][]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md, _ := metadata.FromOutgoingContext(withLogFields(ctx, tt.keys))
+			if len(md) != len(tt.want) {
+				t.Fatalf("metadata = %v, want %v", md, tt.want)
+			}
+			for key, values := range tt.want {
+				if !slices.Equal(md.Get(key), values) {
+					t.Fatalf("metadata = %v, want %v", md, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithLogFieldPropagation(t *testing.T) {
+	client := &fakeClient{name: "greeter"}
+	g := &greeter{}
+	cm := NewGrpcClientManager(nil)
+	t.Cleanup(func() { cm.Shutdown(context.Background()) })
+
+	if err := cm.RegisterClient(client, dialGreeter(t, g), WithLogFieldPropagation()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := logger.AppendCtx(context.Background(), "request_id", "r1")
+	if _, err := helloworld.NewGreeterClient(client.conn).SayHello(ctx, &helloworld.HelloRequest{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.metadata().Get(LogFieldMetadataPrefix + "request_id"); !slices.Equal(got, []string{"r1"}) {
+		t.Fatalf("server metadata = %v, want the request_id field", g.metadata())
+	}
+}
@@ -0,0 +1,138 @@
+package manager
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// callTracker counts outstanding RPCs so that Shutdown can wait for them to drain.
+type callTracker struct {
+	mutex  sync.Mutex
+	active int
+	idle   chan struct{}
+}
+
+func newCallTracker() *callTracker {
+	idle := make(chan struct{})
+	close(idle)
+	return &callTracker{idle: idle}
+}
+
+func (t *callTracker) start() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.active == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.active++
+}
+
+func (t *callTracker) done() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.active--
+	if t.active == 0 {
+		close(t.idle)
+	}
+}
+
+// wait blocks until there are no outstanding calls or the context is done.
+func (t *callTracker) wait(ctx context.Context) error {
+	t.mutex.Lock()
+	idle := t.idle
+	t.mutex.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *callTracker) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		t.start()
+		defer t.done()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func (t *callTracker) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		t.start()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			t.done()
+			return nil, err
+		}
+		return newTrackedStream(stream, desc, t), nil
+	}
+}
+
+// trackedStream marks the call as finished once the stream is over: when
+// RecvMsg returns an error (io.EOF at the regular end of a server stream),
+// when the single response of a client-streaming or unary-like call has been
+// received, or when the stream context is done because the call was cancelled
+// or abandoned.
+type trackedStream struct {
+	grpc.ClientStream
+	tracker       *callTracker
+	serverStreams bool
+	once          sync.Once
+	finished      chan struct{}
+}
+
+func newTrackedStream(stream grpc.ClientStream, desc *grpc.StreamDesc, t *callTracker) *trackedStream {
+	s := &trackedStream{
+		ClientStream:  stream,
+		tracker:       t,
+		serverStreams: desc.ServerStreams,
+		finished:      make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-stream.Context().Done():
+			s.finish()
+		case <-s.finished:
+		}
+	}()
+	return s
+}
+
+func (s *trackedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil || !s.serverStreams {
+		s.finish()
+	}
+	return err
+}
+
+func (s *trackedStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err != nil && err != io.EOF {
+		s.finish()
+	}
+	return err
+}
+
+func (s *trackedStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil && err != io.EOF {
+		s.finish()
+	}
+	return md, err
+}
+
+func (s *trackedStream) finish() {
+	s.once.Do(func() {
+		close(s.finished)
+		s.tracker.done()
+	})
+}
@@ -0,0 +1,93 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+type fakeStream struct {
+	grpc.ClientStream
+	ctx  context.Context
+	recv error
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+func (s *fakeStream) RecvMsg(any) error        { return s.recv }
+
+func startStream(t *testing.T, tracker *callTracker, ctx context.Context, desc *grpc.StreamDesc, recv error) grpc.ClientStream {
+	t.Helper()
+
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &fakeStream{ctx: ctx, recv: recv}, nil
+	}
+	stream, err := tracker.streamInterceptor()(ctx, desc, nil, "/test.Service/Method", streamer)
+	if err != nil {
+		t.Fatalf("stream interceptor: %v", err)
+	}
+	return stream
+}
+
+func assertIdle(t *testing.T, tracker *callTracker, want bool) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if idle := tracker.wait(ctx) == nil; idle != want {
+		t.Fatalf("tracker idle = %v, want %v", idle, want)
+	}
+}
+
+func TestTrackedStreamFinishes(t *testing.T) {
+	tests := []struct {
+		name string
+		desc grpc.StreamDesc
+		recv error
+		// cancel the stream context instead of receiving
+		cancel bool
+		idle   bool
+	}{
+		{name: "server stream message", desc: grpc.StreamDesc{ServerStreams: true}, idle: false},
+		{name: "server stream end", desc: grpc.StreamDesc{ServerStreams: true}, recv: io.EOF, idle: true},
+		{name: "server stream error", desc: grpc.StreamDesc{ServerStreams: true}, recv: errors.New("broken"), idle: true},
+		{name: "client stream response", desc: grpc.StreamDesc{ClientStreams: true}, idle: true},
+		{name: "cancelled", desc: grpc.StreamDesc{ServerStreams: true}, cancel: true, idle: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newCallTracker()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			stream := startStream(t, tracker, ctx, &tt.desc, tt.recv)
+			assertIdle(t, tracker, false)
+
+			if tt.cancel {
+				cancel()
+			} else {
+				stream.RecvMsg(nil)
+			}
+			assertIdle(t, tracker, tt.idle)
+		})
+	}
+}
+
+func TestTrackedStreamCountsOnce(t *testing.T) {
+	tracker := newCallTracker()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	first := startStream(t, tracker, ctx, &grpc.StreamDesc{ServerStreams: true}, io.EOF)
+	startStream(t, tracker, context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil)
+
+	first.RecvMsg(nil)
+	first.RecvMsg(nil)
+	cancel()
+
+	// the second stream is still open
+	assertIdle(t, tracker, false)
+}
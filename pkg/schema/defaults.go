@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ApplyDefaults fills the keys missing from values, a decoded config tree such
+// as viper.AllSettings, with the default tags of t. Lists of structs get the
+// defaults of their element type; keys present in values are never replaced,
+// so an explicit false or 0 is kept.
+func ApplyDefaults(t reflect.Type, values map[string]any) {
+	for _, f := range fields(t) {
+		value, ok := lookup(values, f.key)
+		switch {
+		case isStruct(f.Type):
+			nested, isMap := value.(map[string]any)
+			if !ok || !isMap {
+				nested = map[string]any{}
+			}
+			ApplyDefaults(f.Type, nested)
+			if len(nested) > 0 {
+				values[f.key] = nested
+			}
+		case f.Type.Kind() == reflect.Slice && isStruct(f.Type.Elem()):
+			items, _ := value.([]any)
+			for _, item := range items {
+				if nested, isMap := item.(map[string]any); isMap {
+					ApplyDefaults(f.Type.Elem(), nested)
+				}
+			}
+		case !ok:
+			if def, found := f.defaultTag(); found {
+				values[f.key] = def
+			}
+		}
+	}
+}
+
+// lookup finds key in a tree decoded from yaml, flags or the environment,
+// where keys may differ in case.
+func lookup(values map[string]any, key string) (any, bool) {
+	if v, ok := values[key]; ok {
+		return v, true
+	}
+	for k, v := range values {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// SetDefaults sets the zero fields of the struct ptr points to from their
+// default tags, for configs built in code rather than loaded from files.
+// Nested structs and the elements of lists are filled as well, also behind
+// pointers; a nil pointer stays nil.
+func SetDefaults(ptr any) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("schema: SetDefaults needs a pointer to a struct, got %T", ptr)
+	}
+	return setDefaults(v.Elem())
+}
+
+func setDefaults(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		switch {
+		case isStructValue(f.Type):
+			if sv, ok := structValue(fv); ok {
+				if err := setDefaults(sv); err != nil {
+					return err
+				}
+			}
+		case f.Type.Kind() == reflect.Slice && isStructValue(f.Type.Elem()):
+			for j := 0; j < fv.Len(); j++ {
+				if sv, ok := structValue(fv.Index(j)); ok {
+					if err := setDefaults(sv); err != nil {
+						return err
+					}
+				}
+			}
+		default:
+			def, ok := f.Tag.Lookup("default")
+			if !ok || !fv.IsZero() {
+				continue
+			}
+			if err := setValue(fv, def); err != nil {
+				return fmt.Errorf("schema: default of %s.%s: %w", t.Name(), f.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isStructValue reports whether values of t are a config struct or a pointer to one.
+func isStructValue(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return isStruct(t)
+}
+
+// structValue returns the struct v holds or points to, false for a nil pointer.
+func structValue(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
@@ -0,0 +1,122 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Example writes an annotated YAML config for t: every key with its default
+// value, or commented out with a placeholder, and a comment built from its
+// desc and validate tags.
+func Example(t reflect.Type) []byte {
+	var b bytes.Buffer
+	writeExample(&b, t, 0)
+	return b.Bytes()
+}
+
+func writeExample(b *bytes.Buffer, t reflect.Type, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for i, f := range fields(t) {
+		if depth == 0 && i > 0 {
+			b.WriteByte('\n')
+		}
+		for _, line := range annotations(f) {
+			fmt.Fprintf(b, "%s# %s\n", indent, line)
+		}
+		if isStruct(f.Type) {
+			fmt.Fprintf(b, "%s%s:\n", indent, f.key)
+			writeExample(b, f.Type, depth+1)
+			continue
+		}
+		// optional keys without a default stay unset, so that the example remains valid
+		own, _, _ := f.validateTags()
+		_, hasDefault := f.defaultTag()
+		prefix := indent
+		if !hasDefault && !hasRule(own, "required") && f.Type.Kind() != reflect.Map && f.Type.Kind() != reflect.Slice {
+			prefix += "# "
+		}
+		fmt.Fprintf(b, "%s%s: %s\n", prefix, f.key, exampleValue(f))
+	}
+}
+
+func annotations(f field) []string {
+	var lines []string
+	if desc := f.description(); desc != "" {
+		lines = append(lines, desc)
+	}
+
+	own, keys, elems := f.validateTags()
+	var notes []string
+	_, hasDefault := f.defaultTag()
+	if hasRule(own, "required") && !hasDefault && !isStruct(f.Type) {
+		notes = append(notes, "required")
+	}
+	for _, rule := range append(own, elems...) {
+		tag, param, _ := strings.Cut(rule, "=")
+		switch tag {
+		case "oneof":
+			notes = append(notes, "one of: "+strings.Join(strings.Fields(param), ", "))
+		case "hostport":
+			notes = append(notes, "host:port")
+		case "dial_target":
+			notes = append(notes, "host:port or a gRPC target such as dns:///host:port")
+		case "url_scheme":
+			notes = append(notes, "URL: "+strings.Join(strings.Fields(param), " or "))
+		}
+	}
+	for _, rule := range keys {
+		if tag, param, _ := strings.Cut(rule, "="); tag == "oneof" {
+			notes = append(notes, "keys: "+strings.Join(strings.Fields(param), ", "))
+		}
+	}
+	if f.Type == durationType {
+		notes = append(notes, "duration, e.g. 500ms or 1m")
+	}
+	if len(notes) > 0 {
+		lines = append(lines, "("+strings.Join(notes, "; ")+")")
+	}
+	return lines
+}
+
+func exampleValue(f field) string {
+	def, ok := f.defaultTag()
+	switch {
+	case f.Type.Kind() == reflect.Map:
+		return "{}"
+	case f.Type.Kind() == reflect.Slice && !ok:
+		return "[]"
+	case !ok && f.Type.Kind() == reflect.String:
+		return `""`
+	case !ok:
+		return scalar(reflect.Zero(f.Type).Interface())
+	case f.Type == durationType:
+		return scalar(def)
+	}
+	value, err := ParseDefault(f.Type, def)
+	if err != nil {
+		return scalar(def)
+	}
+	return scalar(value)
+}
+
+func scalar(value any) string {
+	if d, ok := value.(interface{ String() string }); ok && reflect.TypeOf(value) == durationType {
+		value = d.String()
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice {
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = scalar(v.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(string(out))
+}
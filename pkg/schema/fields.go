@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is a config key of a struct: an exported field named after its
+// mapstructure tag. Fields squashed into their parent are listed in its place.
+type field struct {
+	reflect.StructField
+	key string
+}
+
+func (f field) defaultTag() (string, bool) {
+	return f.Tag.Lookup("default")
+}
+
+func (f field) description() string {
+	return f.Tag.Get("desc")
+}
+
+// validateTags splits the validate tag into the rules of the field itself,
+// of its map keys and of its list or map elements.
+func (f field) validateTags() (own, keys, elems []string) {
+	tag := f.Tag.Get("validate")
+	if tag == "" {
+		return nil, nil, nil
+	}
+	rules := strings.Split(tag, ",")
+	i := 0
+	for ; i < len(rules) && rules[i] != "dive"; i++ {
+		own = append(own, rules[i])
+	}
+	if i < len(rules) {
+		i++ // dive
+	}
+	if i < len(rules) && rules[i] == "keys" {
+		for i++; i < len(rules) && rules[i] != "endkeys"; i++ {
+			keys = append(keys, rules[i])
+		}
+		i++ // endkeys
+	}
+	if i < len(rules) {
+		elems = rules[i:]
+	}
+	return own, keys, elems
+}
+
+func fields(t reflect.Type) []field {
+	var result []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && strings.Contains(opts, "squash") {
+			result = append(result, fields(f.Type)...)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		result = append(result, field{StructField: f, key: name})
+	}
+	return result
+}
+
+// isStruct reports whether values of t are configured key by key.
+func isStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != durationType
+}
+
+// ParseDefault converts the default tag s to a value of type t. Durations are
+// parsed with time.ParseDuration, lists are comma separated.
+func ParseDefault(t reflect.Type, s string) (any, error) {
+	v := reflect.New(t).Elem()
+	if err := setValue(v, s); err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+func setValue(v reflect.Value, s string) error {
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		items := strings.Split(s, ",")
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(slice.Index(i), strings.TrimSpace(item)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("defaults are not supported for %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package schema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DraftURL is the JSON Schema dialect of the generated schemas.
+const DraftURL = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern matches the durations accepted by time.ParseDuration.
+const durationPattern = `^(0|(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+)$`
+
+// Schema is a JSON Schema document or subschema.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	PropertyNames        *Schema            `json:"propertyNames,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Format               string             `json:"format,omitempty"`
+}
+
+// Generate builds the JSON Schema of the config struct t from its mapstructure,
+// validate, default and desc tags.
+func Generate(t reflect.Type, title string) *Schema {
+	s := typeSchema(t, nil)
+	s.Schema = DraftURL
+	s.Title = title
+	return s
+}
+
+func typeSchema(t reflect.Type, rules []string) *Schema {
+	var s *Schema
+	switch {
+	case t == durationType:
+		s = &Schema{Type: "string", Pattern: durationPattern}
+	case t.Kind() == reflect.Struct:
+		s = &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+		for _, f := range fields(t) {
+			own, keys, elems := f.validateTags()
+			prop := typeSchema(f.Type, own)
+			switch f.Type.Kind() {
+			case reflect.Map:
+				prop.AdditionalProperties = typeSchema(f.Type.Elem(), elems)
+				if len(keys) > 0 {
+					prop.PropertyNames = typeSchema(f.Type.Key(), keys)
+				}
+			case reflect.Slice:
+				prop.Items = typeSchema(f.Type.Elem(), elems)
+			}
+			prop.Description = f.description()
+			if def, ok := f.defaultTag(); ok {
+				if value, err := ParseDefault(f.Type, def); err == nil && f.Type != durationType {
+					prop.Default = value
+				} else {
+					prop.Default = def
+				}
+			} else if hasRule(own, "required") && (!isStruct(f.Type) || len(prop.Required) > 0) {
+				// a struct is only required when one of its keys has no default
+				s.Required = append(s.Required, f.key)
+			}
+			s.Properties[f.key] = prop
+		}
+		return s
+	case t.Kind() == reflect.Map:
+		s = &Schema{Type: "object"}
+	case t.Kind() == reflect.Slice:
+		s = &Schema{Type: "array"}
+	case t.Kind() == reflect.Bool:
+		s = &Schema{Type: "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		s = &Schema{Type: "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		s = &Schema{Type: "number"}
+	default:
+		s = &Schema{Type: "string"}
+	}
+	applyRules(s, rules)
+	return s
+}
+
+// applyRules translates the validate rules that have a JSON Schema counterpart.
+func applyRules(s *Schema, rules []string) {
+	for _, rule := range rules {
+		tag, param, _ := strings.Cut(rule, "=")
+		switch tag {
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				s.Enum = append(s.Enum, value)
+			}
+		case "gte", "min":
+			s.Minimum = number(param)
+		case "gt":
+			s.ExclusiveMinimum = number(param)
+		case "lte", "max":
+			s.Maximum = number(param)
+		case "required":
+			if s.Type == "string" {
+				one := 1
+				s.MinLength = &one
+			}
+		case "url", "url_scheme":
+			s.Format = "uri"
+		case "duration":
+			if s.Type == "string" {
+				s.Pattern = durationPattern
+			}
+		case "hostport":
+			s.Pattern = `^.*:[0-9]{1,5}$`
+		case "file_mode":
+			// an octal string or the integer YAML reads 0660 as
+			s.Type = ""
+			s.OneOf = []*Schema{
+				{Type: "string", Pattern: `^0?[0-7]{3}$`},
+				{Type: "integer", Minimum: number("0"), Maximum: number("511")},
+			}
+		}
+	}
+}
+
+func hasRule(rules []string, tag string) bool {
+	for _, rule := range rules {
+		if name, _, _ := strings.Cut(rule, "="); name == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func number(param string) *float64 {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,173 @@
+package schema
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testTLS struct {
+	Enabled bool   `mapstructure:"enabled" default:"true"`
+	Retries int    `mapstructure:"retries" validate:"gte=0" default:"3"`
+	Name    string `mapstructure:"name" default:"server"`
+}
+
+type Sink struct {
+	Format string `mapstructure:"format" validate:"omitempty,oneof=json text" default:"json" desc:"Record format"`
+}
+
+type testConfig struct {
+	Sink `mapstructure:",squash"`
+
+	Address  string            `mapstructure:"address" validate:"required,hostport" desc:"Listen address"`
+	Timeout  time.Duration     `mapstructure:"timeout" validate:"duration" default:"5s"`
+	TLS      testTLS           `mapstructure:"tls"`
+	Backup   *testTLS          `mapstructure:"backup"`
+	Sinks    []Sink        `mapstructure:"sinks" validate:"dive"`
+	Pointers []*Sink       `mapstructure:"pointers"`
+	Levels   map[string]string `mapstructure:"levels" validate:"dive,keys,oneof=debug info,endkeys,required"`
+}
+
+func TestApplyDefaultsKeepsExplicitValues(t *testing.T) {
+	values := map[string]any{
+		"tls":   map[string]any{"enabled": false, "Retries": 0, "name": ""},
+		"sinks": []any{map[string]any{}, map[string]any{"format": "text"}},
+	}
+	ApplyDefaults(reflect.TypeOf(testConfig{}), values)
+
+	tls := values["tls"].(map[string]any)
+	if tls["enabled"] != false || tls["Retries"] != 0 || tls["name"] != "" {
+		t.Fatalf("tls = %v, want the explicit values", tls)
+	}
+	if _, ok := tls["retries"]; ok {
+		t.Fatalf("tls = %v, want the key found regardless of case", tls)
+	}
+	if values["format"] != "json" || values["timeout"] != "5s" {
+		t.Fatalf("values = %v, want the defaults of the squashed and top-level keys", values)
+	}
+	sinks := values["sinks"].([]any)
+	if sinks[0].(map[string]any)["format"] != "json" || sinks[1].(map[string]any)["format"] != "text" {
+		t.Fatalf("sinks = %v, want the default only in the first", sinks)
+	}
+	if _, ok := values["address"]; ok {
+		t.Fatal("a key without a default was added")
+	}
+}
+
+func TestApplyDefaultsFillsMissingSections(t *testing.T) {
+	values := map[string]any{}
+	ApplyDefaults(reflect.TypeOf(testConfig{}), values)
+
+	want := map[string]any{"enabled": "true", "retries": "3", "name": "server"}
+	if tls := values["tls"]; !reflect.DeepEqual(tls, want) {
+		t.Fatalf("tls = %v, want %v", tls, want)
+	}
+}
+
+func TestSetDefaults(t *testing.T) {
+	cfg := &testConfig{
+		TLS:      testTLS{Name: "custom"},
+		Backup:   &testTLS{},
+		Sinks:    []Sink{{}, {Format: "text"}},
+		Pointers: []*Sink{{}, nil},
+	}
+	if err := SetDefaults(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Format != "json" || cfg.Timeout != 5*time.Second {
+		t.Errorf("format = %q, timeout = %v, want the defaults", cfg.Format, cfg.Timeout)
+	}
+	if want := (testTLS{Enabled: true, Retries: 3, Name: "custom"}); cfg.TLS != want {
+		t.Errorf("tls = %+v, want %+v", cfg.TLS, want)
+	}
+	if want := (testTLS{Enabled: true, Retries: 3, Name: "server"}); *cfg.Backup != want {
+		t.Errorf("backup = %+v, want %+v", *cfg.Backup, want)
+	}
+	if cfg.Sinks[0].Format != "json" || cfg.Sinks[1].Format != "text" {
+		t.Errorf("sinks = %+v", cfg.Sinks)
+	}
+	if cfg.Pointers[0].Format != "json" || cfg.Pointers[1] != nil {
+		t.Errorf("pointers = %v, %v", cfg.Pointers[0], cfg.Pointers[1])
+	}
+
+	// a nil section stays nil
+	empty := &testConfig{}
+	if err := SetDefaults(empty); err != nil {
+		t.Fatal(err)
+	}
+	if empty.Backup != nil {
+		t.Error("SetDefaults allocated a nil section")
+	}
+}
+
+func TestSetDefaultsErrors(t *testing.T) {
+	if err := SetDefaults(testConfig{}); err == nil {
+		t.Error("SetDefaults accepted a struct value")
+	}
+	type invalid struct {
+		Count int `default:"many"`
+	}
+	if err := SetDefaults(&invalid{}); err == nil || !strings.Contains(err.Error(), "invalid.Count") {
+		t.Errorf("SetDefaults = %v, want the field of the invalid default", err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate(reflect.TypeOf(testConfig{}), "Test")
+	if s.Schema != DraftURL || s.Title != "Test" || s.AdditionalProperties != false {
+		t.Fatalf("schema = %+v", s)
+	}
+	if !slices.Equal(s.Required, []string{"address"}) {
+		t.Errorf("required = %v, want [address]", s.Required)
+	}
+
+	address := s.Properties["address"]
+	if address.Description != "Listen address" || address.Pattern == "" || *address.MinLength != 1 {
+		t.Errorf("address = %+v", address)
+	}
+	format := s.Properties["format"]
+	if format.Default != "json" || !reflect.DeepEqual(format.Enum, []any{"json", "text"}) || format.Description != "Record format" {
+		t.Errorf("format = %+v", format)
+	}
+	timeout := s.Properties["timeout"]
+	if timeout.Type != "string" || timeout.Pattern != durationPattern || timeout.Default != "5s" {
+		t.Errorf("timeout = %+v", timeout)
+	}
+	retries := s.Properties["tls"].Properties["retries"]
+	if retries.Type != "integer" || retries.Default != 3 || retries.Minimum == nil || *retries.Minimum != 0 {
+		t.Errorf("tls.retries = %+v", retries)
+	}
+	if enabled := s.Properties["tls"].Properties["enabled"]; enabled.Type != "boolean" || enabled.Default != true {
+		t.Errorf("tls.enabled = %+v", enabled)
+	}
+	if items := s.Properties["sinks"].Items; items == nil || items.Properties["format"].Default != "json" {
+		t.Errorf("sinks.items = %+v", items)
+	}
+	levels := s.Properties["levels"]
+	if levels.PropertyNames == nil || !reflect.DeepEqual(levels.PropertyNames.Enum, []any{"debug", "info"}) {
+		t.Errorf("levels.propertyNames = %+v", levels.PropertyNames)
+	}
+	if values, ok := levels.AdditionalProperties.(*Schema); !ok || *values.MinLength != 1 {
+		t.Errorf("levels.additionalProperties = %+v", levels.AdditionalProperties)
+	}
+}
+
+func TestExample(t *testing.T) {
+	example := string(Example(reflect.TypeOf(testConfig{})))
+
+	for _, want := range []string{
+		"# Record format\n# (one of: json, text)\nformat: json\n",
+		"# Listen address\n# (required; host:port)\naddress: \"\"\n",
+		"# (duration, e.g. 500ms or 1m)\ntimeout: 5s\n",
+		"tls:\n  enabled: true\n  retries: 3\n  name: server\n",
+		"sinks: []\n",
+		"# (keys: debug, info)\nlevels: {}\n",
+	} {
+		if !strings.Contains(example, want) {
+			t.Errorf("example lacks %q:\n%s", want, example)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// FieldError is a failed validation of one field. Path follows the mapstructure
+// keys, e.g. logger.sinks[0].output. Values are left out of the message on
+// purpose, as they may hold secrets.
+type FieldError struct {
+	Path    string
+	Tag     string
+	Param   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Errors are all the validation failures of a struct.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d invalid settings:", len(e))
+	for _, fe := range e {
+		b.WriteString("\n  ")
+		b.WriteString(fe.Error())
+	}
+	return b.String()
+}
+
+func message(tag, param string) string {
+	switch tag {
+	case "required":
+		return "is required"
+	case "required_if":
+		return "is required when " + conditions(param)
+	case "required_with":
+		return "is required when " + keyList(param) + " is set"
+	case "required_without":
+		return "is required when " + keyList(param) + " is not set"
+	case "excluded_with":
+		return "must not be set together with " + keyList(param)
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "gte", "min":
+		return "must be at least " + param
+	case "gt":
+		return "must be greater than " + param
+	case "lte", "max":
+		return "must be at most " + param
+	case "lt":
+		return "must be less than " + param
+	case "startswith":
+		return "must start with " + param
+	case "url":
+		return "must be a valid URL"
+	case "url_scheme":
+		return "must be a URL with scheme " + strings.Join(strings.Fields(param), " or ")
+	case "hostport":
+		return "must be host:port, e.g. localhost:50051 or :50051"
+	case "dial_target":
+		return "must be host:port or a gRPC target such as dns:///host:port or unix:///path/to.sock"
+	case "listen_address":
+		return "must be host:port, tcp://host:port, unix:///path/to.sock or fd://3"
+	case "file_mode":
+		return "must be octal permissions such as 0660"
+	case "duration":
+		return "must be a non-negative duration, e.g. 5s or 1m30s"
+	case "file":
+		return "must be an existing file"
+	case "dir":
+		return "must be an existing directory"
+	case "filepath":
+		return "must be a file path"
+	}
+	if param != "" {
+		return fmt.Sprintf("failed %s=%s validation", tag, param)
+	}
+	return fmt.Sprintf("failed %s validation", tag)
+}
+
+// conditions turns a required_if param such as "Output file" into "output is file".
+func conditions(param string) string {
+	fields := strings.Fields(param)
+	parts := make([]string, 0, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		parts = append(parts, keyName(fields[i])+" is "+fields[i+1])
+	}
+	return strings.Join(parts, " and ")
+}
+
+func keyList(param string) string {
+	fields := strings.Fields(param)
+	for i, field := range fields {
+		fields[i] = keyName(field)
+	}
+	return strings.Join(fields, " or ")
+}
+
+// keyName converts a Go field name referenced by a tag param to its config key, e.g. MaxBytes to max_bytes.
+func keyName(field string) string {
+	var b strings.Builder
+	for i, r := range field {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	hostnameRe   = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,62}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,62}[a-zA-Z0-9])?)*$`)
+)
+
+var customTags = map[string]validator.Func{
+	"hostport":       isHostPort,
+	"duration":       isDuration,
+	"dial_target":    isDialTarget,
+	"url_scheme":     hasURLScheme,
+	"listen_address": isListenAddress,
+	"file_mode":      isFileMode,
+}
+
+// isHostPort accepts host:port with a host name or an IP address and a numeric
+// port; the host may be empty to listen on every interface, the port may be 0
+// to pick a free one.
+func isHostPort(fl validator.FieldLevel) bool {
+	return validHostPort(fl.Field().String())
+}
+
+func validHostPort(address string) bool {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n > 65535 {
+		return false
+	}
+	return host == "" || net.ParseIP(host) != nil || hostnameRe.MatchString(host)
+}
+
+// isDuration accepts a non-negative time.Duration or a string parsed by time.ParseDuration.
+func isDuration(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch {
+	case field.Type() == durationType:
+		return field.Int() >= 0
+	case field.Kind() == reflect.String:
+		d, err := time.ParseDuration(field.String())
+		return err == nil && d >= 0
+	}
+	return false
+}
+
+// isDialTarget accepts what grpc.NewClient dials: host:port or a target with
+// a resolver scheme such as dns:///host:port, unix:///path or unix:relative.
+func isDialTarget(fl validator.FieldLevel) bool {
+	target := fl.Field().String()
+	if validHostPort(target) {
+		return true
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+	return strings.TrimPrefix(u.Path, "/") != "" || u.Opaque != ""
+}
+
+// hasURLScheme accepts an absolute URL with a host and one of the space separated schemes of the param.
+func hasURLScheme(fl validator.FieldLevel) bool {
+	u, err := url.Parse(fl.Field().String())
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return slices.Contains(strings.Fields(fl.Param()), strings.ToLower(u.Scheme))
+}
+
+// isListenAddress accepts host:port, tcp://host:port, unix:///path and fd://
+// followed by an inherited descriptor number or name.
+func isListenAddress(fl validator.FieldLevel) bool {
+	address := fl.Field().String()
+	if path, ok := strings.CutPrefix(address, "unix://"); ok {
+		return path != ""
+	}
+	if ref, ok := strings.CutPrefix(address, "fd://"); ok {
+		return ref != ""
+	}
+	return validHostPort(strings.TrimPrefix(address, "tcp://"))
+}
+
+// isFileMode accepts permission bits such as 0660, given as a number or as
+// an octal string.
+func isFileMode(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.Uint32:
+		return field.Uint() <= 0o777
+	case reflect.String:
+		mode, err := strconv.ParseUint(field.String(), 8, 32)
+		return err == nil && mode <= 0o777
+	}
+	return false
+}
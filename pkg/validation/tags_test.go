@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDialTarget(t *testing.T) {
+	type target struct {
+		Host string `mapstructure:"host" validate:"dial_target"`
+	}
+
+	tests := []struct {
+		host  string
+		valid bool
+	}{
+		{host: "localhost:50051", valid: true},
+		{host: "10.0.0.1:443", valid: true},
+		{host: "dns:///status.internal:50051", valid: true},
+		{host: "dns://8.8.8.8/status.internal:50051", valid: true},
+		{host: "unix:///run/status.sock", valid: true},
+		{host: "unix:status.sock", valid: true},
+		{host: "passthrough:///bufconn", valid: true},
+		{host: "localhost", valid: false},
+		{host: "dns:///", valid: false},
+		{host: "::bad", valid: false},
+	}
+
+	for _, tt := range tests {
+		err := Validate(&target{Host: tt.host})
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("%q: valid = %v, want %v (%v)", tt.host, valid, tt.valid, err)
+		}
+	}
+}
+
+func TestHostPort(t *testing.T) {
+	type listener struct {
+		Address string `mapstructure:"address" validate:"hostport"`
+	}
+
+	tests := []struct {
+		address string
+		valid   bool
+	}{
+		{address: "localhost:50051", valid: true},
+		{address: ":50051", valid: true},
+		{address: "127.0.0.1:0", valid: true},
+		{address: "[::1]:443", valid: true},
+		{address: "grpc.internal:65535", valid: true},
+		{address: "localhost", valid: false},
+		{address: "localhost:65536", valid: false},
+		{address: "localhost:http", valid: false},
+		{address: "bad_host:80", valid: false},
+		{address: "", valid: false},
+	}
+
+	for _, tt := range tests {
+		err := Validate(&listener{Address: tt.address})
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("%q: valid = %v, want %v (%v)", tt.address, valid, tt.valid, err)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	type timeouts struct {
+		Timeout  time.Duration `mapstructure:"timeout" validate:"duration"`
+		Interval string        `mapstructure:"interval" validate:"duration"`
+	}
+
+	tests := []struct {
+		name  string
+		value timeouts
+		valid bool
+	}{
+		{name: "valid", value: timeouts{Timeout: time.Second, Interval: "1m30s"}, valid: true},
+		{name: "zero", value: timeouts{Interval: "0s"}, valid: true},
+		{name: "negative", value: timeouts{Timeout: -time.Second, Interval: "1s"}, valid: false},
+		{name: "negative string", value: timeouts{Interval: "-1s"}, valid: false},
+		{name: "not a duration", value: timeouts{Interval: "soon"}, valid: false},
+	}
+
+	for _, tt := range tests {
+		err := Validate(&tt.value)
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("%s: valid = %v, want %v (%v)", tt.name, valid, tt.valid, err)
+		}
+	}
+}
+
+func TestURLScheme(t *testing.T) {
+	type collector struct {
+		URL string `mapstructure:"url" validate:"url_scheme=http https"`
+	}
+
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{url: "http://collector:8080/logs", valid: true},
+		{url: "HTTPS://collector/logs", valid: true},
+		{url: "ftp://collector/logs", valid: false},
+		{url: "collector/logs", valid: false},
+		{url: "http:///logs", valid: false},
+	}
+
+	for _, tt := range tests {
+		err := Validate(&collector{URL: tt.url})
+		if valid := err == nil; valid != tt.valid {
+			t.Errorf("%q: valid = %v, want %v (%v)", tt.url, valid, tt.valid, err)
+		}
+	}
+}
@@ -1,20 +1,103 @@
 package validation
 
 import (
-	"fmt"
+	"errors"
+	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
 
+// squashMarker names the path segment of fields squashed into their parent,
+// so that it can be dropped from error paths.
+const squashMarker = "<squash>"
+
 type Validatable interface {
 	Validate() error
 }
 
-func Validate(cfg Validatable) error {
-	validate := validator.New(validator.WithRequiredStructEnabled())
+// validate is shared by every caller: it caches struct metadata, and custom
+// tags and struct rules are registered on it once.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(fieldName)
+	for tag, fn := range customTags {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			panic(err)
+		}
+	}
+	return v
+}
+
+// fieldName names fields after their mapstructure key, so that errors use the
+// same path as the config file, e.g. grpc.address.
+func fieldName(field reflect.StructField) string {
+	name, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+	switch {
+	case name == "-":
+		return "-"
+	case field.Anonymous && strings.Contains(opts, "squash"):
+		return squashMarker
+	case name == "":
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// Violation is a failed cross-field rule. Field is the path of the offending
+// field relative to the validated struct, e.g. http.url; Tag and Param select
+// the message the same way a validate tag would, e.g. required_if and "Output http".
+type Violation struct {
+	Field string
+	Tag   string
+	Param string
+}
+
+// RegisterStructRule adds a cross-field rule checked for every value of type T,
+// including nested ones. Register rules from init functions: registration is
+// not safe concurrently with validation.
+func RegisterStructRule[T any](rule func(v T) []Violation) {
+	var zero T
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		v, ok := sl.Current().Interface().(T)
+		if !ok {
+			return
+		}
+		for _, violation := range rule(v) {
+			sl.ReportError(nil, violation.Field, violation.Field, violation.Tag, violation.Param)
+		}
+	}, zero)
+}
+
+// Validate checks cfg against its validate tags and the registered struct rules.
+// Validation failures are returned as Errors.
+func Validate(cfg any) error {
 	err := validate.Struct(cfg)
-	if err != nil {
-		return fmt.Errorf("error validate config: %w", err)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	result := make(Errors, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		result = append(result, FieldError{
+			Path:    fieldPath(fe.Namespace()),
+			Tag:     fe.Tag(),
+			Param:   fe.Param(),
+			Message: message(fe.Tag(), fe.Param()),
+		})
+	}
+	return result
+}
+
+// fieldPath drops the name of the validated type and the squashed fields from a namespace.
+func fieldPath(namespace string) string {
+	_, path, found := strings.Cut(namespace, ".")
+	if !found {
+		return namespace
 	}
-	return nil
+	path = strings.ReplaceAll(path, squashMarker+".", "")
+	return strings.ReplaceAll(path, "."+squashMarker, "")
 }
@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type Sink struct {
+	Output string `mapstructure:"output" validate:"omitempty,oneof=stdout file"`
+	Path   string `mapstructure:"path"`
+}
+
+type testLogger struct {
+	Sink `mapstructure:",squash"`
+
+	Sinks []Sink `mapstructure:"sinks" validate:"dive"`
+}
+
+type testConfig struct {
+	Address string     `mapstructure:"address" validate:"required"`
+	Logger  testLogger `mapstructure:"logger"`
+}
+
+func init() {
+	RegisterStructRule(func(sink Sink) []Violation {
+		if sink.Output == "file" && sink.Path == "" {
+			return []Violation{{Field: "path", Tag: "required_if", Param: "Output file"}}
+		}
+		return nil
+	})
+}
+
+func TestValidateErrorPaths(t *testing.T) {
+	cfg := &testConfig{
+		Logger: testLogger{
+			Sink: Sink{Output: "kafka"},
+			Sinks:    []Sink{{Output: "stdout"}, {Output: "file"}},
+		},
+	}
+
+	err := Validate(cfg)
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate = %v, want Errors", err)
+	}
+
+	want := map[string]string{
+		"address":              "is required",
+		"logger.output":        "must be one of: stdout, file",
+		"logger.sinks[1].path": "is required when output is file",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("errors = %v, want %d", errs, len(want))
+	}
+	for _, fe := range errs {
+		if msg, ok := want[fe.Path]; !ok || fe.Message != msg {
+			t.Errorf("%s: %s, want %q", fe.Path, fe.Message, msg)
+		}
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, "3 invalid settings:\n  ") {
+		t.Errorf("Error = %q, want every setting on its own line", msg)
+	}
+}
+
+func TestValidateSingleError(t *testing.T) {
+	err := Validate(&testConfig{})
+	if err == nil || err.Error() != "address: is required" {
+		t.Fatalf("Validate = %v, want the single error without a header", err)
+	}
+	if err := Validate(&testConfig{Address: "localhost:1"}); err != nil {
+		t.Fatalf("Validate = %v", err)
+	}
+}